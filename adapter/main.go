@@ -20,6 +20,7 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	"runtime"
 	"sync"
@@ -66,6 +67,7 @@ var logger = klogr.New().WithName("keda_metrics_adapter")
 var (
 	prometheusMetricsPort     int
 	prometheusMetricsPath     string
+	scalersHealthPort         int
 	adapterClientRequestQPS   float32
 	adapterClientRequestBurst int
 )
@@ -113,6 +115,18 @@ func (a *Adapter) makeProvider(ctx context.Context, globalHTTPTimeout time.Durat
 		return nil, nil, fmt.Errorf("failed to get watch namespace (%s)", err)
 	}
 
+	if scalersHealthPort > 0 {
+		// the health check calls the scaled services, it is kept off the unauthenticated metrics listener and
+		// only served when enabled
+		healthMux := http.NewServeMux()
+		healthMux.Handle(scaling.ScalersHealthPath, scaling.NewHealthHandler(kubeclient, handler))
+		go func() {
+			logger.Info("Starting scalers health server", "port", scalersHealthPort)
+			if err := http.ListenAndServe(fmt.Sprintf(":%v", scalersHealthPort), healthMux); err != nil {
+				logger.Error(err, "scalers health server failed")
+			}
+		}()
+	}
 	prometheusServer := &prommetrics.PrometheusMetricServer{}
 	go func() { prometheusServer.NewServer(fmt.Sprintf(":%v", prometheusMetricsPort), prometheusMetricsPath) }()
 	stopCh := make(chan struct{})
@@ -192,6 +206,7 @@ func main() {
 	cmd.Flags().AddGoFlagSet(flag.CommandLine) // make sure we get the klog flags
 	cmd.Flags().IntVar(&prometheusMetricsPort, "metrics-port", 9022, "Set the port to expose prometheus metrics")
 	cmd.Flags().StringVar(&prometheusMetricsPath, "metrics-path", "/metrics", "Set the path for the prometheus metrics endpoint")
+	cmd.Flags().IntVar(&scalersHealthPort, "scalers-health-port", 0, "Set the port to expose the scalers health endpoint on, disabled when 0")
 	cmd.Flags().Float32Var(&adapterClientRequestQPS, "kube-api-qps", 20.0, "Set the QPS rate for throttling requests sent to the apiserver")
 	cmd.Flags().IntVar(&adapterClientRequestBurst, "kube-api-burst", 30, "Set the burst for throttling requests sent to the apiserver")
 	if err := cmd.Flags().Parse(os.Args); err != nil {
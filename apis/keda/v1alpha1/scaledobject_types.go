@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -150,6 +151,9 @@ type ScaledObjectStatus struct {
 	Conditions Conditions `json:"conditions,omitempty"`
 	// +optional
 	Health map[string]HealthStatus `json:"health,omitempty"`
+	// LastMetricValues holds the last value read from the backend by the triggers able to report it, by metric name
+	// +optional
+	LastMetricValues map[string]resource.Quantity `json:"lastMetricValues,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -24,6 +24,7 @@ package v1alpha1
 import (
 	"k8s.io/api/autoscaling/v2beta2"
 	"k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -647,6 +648,13 @@ func (in *ScaledObjectStatus) DeepCopyInto(out *ScaledObjectStatus) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.LastMetricValues != nil {
+		in, out := &in.LastMetricValues, &out.LastMetricValues
+		*out = make(map[string]resource.Quantity, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaledObjectStatus.
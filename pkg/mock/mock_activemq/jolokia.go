@@ -0,0 +1,302 @@
+// Package mock_activemq provides a fake Jolokia agent of a classic ActiveMQ broker, to exercise the ActiveMQ scaler
+// and validate trigger configurations without running a broker.
+package mock_activemq
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+// JolokiaServer serves Jolokia read, search and version requests on /api/jolokia/ from the attribute values set on
+// it. Reads of a destination without values fail like those of a destination that does not exist.
+type JolokiaServer struct {
+	server *httptest.Server
+
+	lock       sync.Mutex
+	attributes map[string]map[string]interface{}      // destination name to attribute values
+	bridges    map[string]map[string]map[string]int64 // network connector name to bridge name to counters
+	brokers    []string                               // names of the brokers found by searches, localhost by default
+	status     int                                    // Jolokia status of the successful reads
+	username   string
+	password   string
+	failures   []int // HTTP status codes returned by the next requests, in order
+	requests   int
+}
+
+// jolokiaRead is a read of a Jolokia POST request
+type jolokiaRead struct {
+	Type      string      `json:"type"`
+	MBean     string      `json:"mbean"`
+	Attribute interface{} `json:"attribute"` // a single attribute name or a list of them
+}
+
+// NewJolokiaServer starts a fake Jolokia agent, it must be closed with Close
+func NewJolokiaServer() *JolokiaServer {
+	s := &JolokiaServer{
+		attributes: make(map[string]map[string]interface{}),
+		bridges:    make(map[string]map[string]map[string]int64),
+		brokers:    []string{"localhost"},
+		status:     http.StatusOK,
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Close shuts the server down
+func (s *JolokiaServer) Close() {
+	s.server.Close()
+}
+
+// ManagementEndpoint returns the host:port to use as the managementEndpoint of the trigger
+func (s *JolokiaServer) ManagementEndpoint() string {
+	return strings.TrimPrefix(s.server.URL, "http://")
+}
+
+// SetQueueSize sets the QueueSize attribute of the destination
+func (s *JolokiaServer) SetQueueSize(destinationName string, queueSize int64) {
+	s.SetAttribute(destinationName, "QueueSize", queueSize)
+}
+
+// SetAttribute sets an attribute of the destination, such as ConsumerCount or EnqueueCount
+func (s *JolokiaServer) SetAttribute(destinationName, attribute string, value interface{}) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.attributes[destinationName] == nil {
+		s.attributes[destinationName] = make(map[string]interface{})
+	}
+	s.attributes[destinationName][attribute] = value
+}
+
+// SetBrokers sets the names of the brokers whose MBeans are returned by searches
+func (s *JolokiaServer) SetBrokers(brokerNames ...string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.brokers = brokerNames
+}
+
+// SetNetworkBridge sets the counters of a bridge of the network connector, read with a pattern over its bridges
+func (s *JolokiaServer) SetNetworkBridge(connectorName, bridge string, enqueueCounter, dequeueCounter int64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.bridges[connectorName] == nil {
+		s.bridges[connectorName] = make(map[string]map[string]int64)
+	}
+	s.bridges[connectorName][bridge] = map[string]int64{"EnqueueCounter": enqueueCounter, "DequeueCounter": dequeueCounter}
+}
+
+// RemoveNetworkBridges drops all the bridges of the network connector, as when none is connected
+func (s *JolokiaServer) RemoveNetworkBridges(connectorName string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.bridges, connectorName)
+}
+
+// SetStatus sets the Jolokia status of the successful reads, 200 by default, as some gateways answer with another one
+func (s *JolokiaServer) SetStatus(status int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.status = status
+}
+
+// RemoveDestination drops all the attributes of the destination, its reads then fail as not found
+func (s *JolokiaServer) RemoveDestination(destinationName string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.attributes, destinationName)
+}
+
+// RequireBasicAuth makes the server reject requests without the username and password with 401
+func (s *JolokiaServer) RequireBasicAuth(username, password string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.username, s.password = username, password
+}
+
+// FailNext makes the next count requests fail with the HTTP status code, e.g. 503 to exercise retries
+func (s *JolokiaServer) FailNext(count, statusCode int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for i := 0; i < count; i++ {
+		s.failures = append(s.failures, statusCode)
+	}
+}
+
+// Requests returns the number of requests served so far, including the failed ones
+func (s *JolokiaServer) Requests() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.requests
+}
+
+func (s *JolokiaServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.requests++
+
+	if len(s.failures) > 0 {
+		status := s.failures[0]
+		s.failures = s.failures[1:]
+		w.WriteHeader(status)
+		return
+	}
+	if s.username != "" {
+		if username, password, ok := r.BasicAuth(); !ok || username != s.username || password != s.password {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+	}
+	if !strings.HasPrefix(r.URL.Path, "/api/jolokia/") {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	var response interface{}
+	switch request := strings.TrimPrefix(r.URL.Path, "/api/jolokia/"); {
+	case r.Method == http.MethodGet && request == "version":
+		response = s.version()
+	case r.Method == http.MethodGet && strings.HasPrefix(request, "search/"):
+		response = s.search()
+	case r.Method == http.MethodGet && strings.HasPrefix(request, "read/"):
+		i := strings.LastIndex(request, "/")
+		response = s.read(jolokiaRead{Type: "read", MBean: request[len("read/"):i], Attribute: request[i+1:]})
+	case r.Method == http.MethodPost:
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if response, err = s.post(body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// post serves a single or bulk POST request
+func (s *JolokiaServer) post(body []byte) (interface{}, error) {
+	if strings.HasPrefix(strings.TrimSpace(string(body)), "[") {
+		var reads []jolokiaRead
+		if err := json.Unmarshal(body, &reads); err != nil {
+			return nil, err
+		}
+		responses := make([]interface{}, 0, len(reads))
+		for _, read := range reads {
+			responses = append(responses, s.serveRead(read))
+		}
+		return responses, nil
+	}
+	var read jolokiaRead
+	if err := json.Unmarshal(body, &read); err != nil {
+		return nil, err
+	}
+	return s.serveRead(read), nil
+}
+
+func (s *JolokiaServer) serveRead(read jolokiaRead) interface{} {
+	switch read.Type {
+	case "version":
+		return s.version()
+	case "search":
+		return s.search()
+	default:
+		return s.read(read)
+	}
+}
+
+func (s *JolokiaServer) version() interface{} {
+	return map[string]interface{}{
+		"value":     map[string]string{"agent": "1.7.1", "protocol": "7.2"},
+		"timestamp": time.Now().Unix(),
+		"status":    http.StatusOK,
+	}
+}
+
+// search returns the broker MBeans, the only search the scaler sends
+func (s *JolokiaServer) search() interface{} {
+	mbeans := make([]string, 0, len(s.brokers))
+	for _, broker := range s.brokers {
+		mbeans = append(mbeans, fmt.Sprintf("org.apache.activemq:brokerName=%s,type=Broker", broker))
+	}
+	return map[string]interface{}{
+		"value":     mbeans,
+		"timestamp": time.Now().Unix(),
+		"status":    http.StatusOK,
+	}
+}
+
+func (s *JolokiaServer) read(read jolokiaRead) interface{} {
+	properties := make(map[string]string)
+	for _, property := range strings.Split(read.MBean[strings.Index(read.MBean, ":")+1:], ",") {
+		if kv := strings.SplitN(property, "=", 2); len(kv) == 2 {
+			properties[kv[0]] = kv[1]
+		}
+	}
+	if properties["connector"] == "networkConnectors" && properties["networkBridge"] == "*" {
+		return s.readBridges(read, properties)
+	}
+
+	attribute, _ := read.Attribute.(string)
+	attributes, ok := s.attributes[properties["destinationName"]]
+	if !ok {
+		return map[string]interface{}{
+			"error_type": "javax.management.InstanceNotFoundException",
+			"error":      fmt.Sprintf("javax.management.InstanceNotFoundException : %s", read.MBean),
+			"status":     http.StatusNotFound,
+		}
+	}
+	value, ok := attributes[attribute]
+	if !ok {
+		return map[string]interface{}{
+			"error_type": "javax.management.AttributeNotFoundException",
+			"error":      fmt.Sprintf("No such attribute: %s", attribute),
+			"status":     http.StatusNotFound,
+		}
+	}
+	return map[string]interface{}{
+		"value":     value,
+		"timestamp": time.Now().Unix(),
+		"status":    s.status,
+	}
+}
+
+// readBridges serves a pattern read of the bridges of a network connector, the values of the requested attributes
+// by bridge MBean name
+func (s *JolokiaServer) readBridges(read jolokiaRead, properties map[string]string) interface{} {
+	bridges := s.bridges[properties["networkConnectorName"]]
+	if len(bridges) == 0 {
+		return map[string]interface{}{
+			"error_type": "javax.management.InstanceNotFoundException",
+			"error":      fmt.Sprintf("javax.management.InstanceNotFoundException : %s", read.MBean),
+			"status":     http.StatusNotFound,
+		}
+	}
+	attributes, _ := read.Attribute.([]interface{})
+	value := make(map[string]map[string]int64, len(bridges))
+	for bridge, counters := range bridges {
+		mbean := fmt.Sprintf("org.apache.activemq:brokerName=%s,connector=networkConnectors,networkBridge=%s,networkConnectorName=%s,type=Broker",
+			properties["brokerName"], bridge, properties["networkConnectorName"])
+		value[mbean] = make(map[string]int64, len(attributes))
+		for _, attribute := range attributes {
+			if counter, ok := counters[fmt.Sprint(attribute)]; ok {
+				value[mbean][fmt.Sprint(attribute)] = counter
+			}
+		}
+	}
+	return map[string]interface{}{
+		"value":     value,
+		"timestamp": time.Now().Unix(),
+		"status":    s.status,
+	}
+}
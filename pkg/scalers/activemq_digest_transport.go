@@ -0,0 +1,248 @@
+package scalers
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// activeMQDigestChallenge is a digest challenge sent by the server in a WWW-Authenticate header (RFC 7616)
+type activeMQDigestChallenge struct {
+	realm     string
+	nonce     string
+	opaque    string
+	algorithm string
+	qop       []string
+	// nonceCount is the number of requests sent with the nonce, incremented atomically
+	nonceCount uint32
+}
+
+// activeMQDigestTransport answers the HTTP digest challenges of the management endpoint. The last challenge is
+// kept and its nonce reused for the following requests, so the handshake only costs an extra request when the
+// server asks for it again, e.g. when the nonce has gone stale.
+type activeMQDigestTransport struct {
+	base     http.RoundTripper
+	username string
+	password string
+
+	lock      sync.Mutex
+	challenge *activeMQDigestChallenge
+}
+
+func newActiveMQDigestTransport(base http.RoundTripper, username, password string) *activeMQDigestTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &activeMQDigestTransport{base: base, username: username, password: password}
+}
+
+// RoundTrip sends the request with the credentials for the last challenge, and answers a new challenge once
+func (t *activeMQDigestTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.lock.Lock()
+	challenge := t.challenge
+	t.lock.Unlock()
+
+	for attempt := 0; ; attempt++ {
+		authReq, err := t.authorize(req, challenge, attempt > 0)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := t.base.RoundTrip(authReq)
+		if err != nil || resp.StatusCode != http.StatusUnauthorized || attempt > 0 {
+			return resp, err
+		}
+
+		next := parseActiveMQDigestChallenge(resp.Header)
+		if next == nil || (req.Body != nil && req.GetBody == nil) {
+			// not a digest challenge, or the body can't be sent again
+			return resp, nil
+		}
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+
+		t.lock.Lock()
+		t.challenge = next
+		t.lock.Unlock()
+		challenge = next
+	}
+}
+
+// CloseIdleConnections lets http.Client.CloseIdleConnections reach the wrapped transport
+func (t *activeMQDigestTransport) CloseIdleConnections() {
+	if transport, ok := t.base.(interface{ CloseIdleConnections() }); ok {
+		transport.CloseIdleConnections()
+	}
+}
+
+// reset forgets the last challenge, the next request starts a new handshake
+func (t *activeMQDigestTransport) reset() {
+	t.lock.Lock()
+	t.challenge = nil
+	t.lock.Unlock()
+}
+
+// authorize returns a copy of the request with the Authorization header answering the challenge, if any
+func (t *activeMQDigestTransport) authorize(req *http.Request, challenge *activeMQDigestChallenge, resend bool) (*http.Request, error) {
+	authReq := req.Clone(req.Context())
+	if resend && req.Body != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		authReq.Body = body
+	}
+	if challenge == nil {
+		return authReq, nil
+	}
+	authorization, err := challenge.authorization(t.username, t.password, req.Method, req.URL.RequestURI())
+	if err != nil {
+		return nil, err
+	}
+	authReq.Header.Set("Authorization", authorization)
+	return authReq, nil
+}
+
+// authorization computes the Authorization header of a request answering the challenge
+func (c *activeMQDigestChallenge) authorization(username, password, method, uri string) (string, error) {
+	var newHash func() hash.Hash
+	algorithm := strings.ToUpper(c.algorithm)
+	switch strings.TrimSuffix(algorithm, "-SESS") {
+	case "", "MD5":
+		newHash = md5.New
+	case "SHA-256":
+		newHash = sha256.New
+	default:
+		return "", fmt.Errorf("unsupported digest algorithm %q requested by the ActiveMQ management endpoint", c.algorithm)
+	}
+	digest := func(parts ...string) string {
+		h := newHash()
+		_, _ = io.WriteString(h, strings.Join(parts, ":"))
+		return hex.EncodeToString(h.Sum(nil))
+	}
+
+	qop := ""
+	if len(c.qop) > 0 {
+		for _, value := range c.qop {
+			if value == "auth" {
+				qop = value
+			}
+		}
+		if qop == "" {
+			return "", fmt.Errorf("unsupported digest qop %q requested by the ActiveMQ management endpoint", strings.Join(c.qop, ","))
+		}
+	}
+
+	cnonce, err := newActiveMQDigestCnonce()
+	if err != nil {
+		return "", err
+	}
+	nonceCount := fmt.Sprintf("%08x", atomic.AddUint32(&c.nonceCount, 1))
+
+	ha1 := digest(username, c.realm, password)
+	if strings.HasSuffix(algorithm, "-SESS") {
+		ha1 = digest(ha1, c.nonce, cnonce)
+	}
+	ha2 := digest(method, uri)
+
+	var response string
+	if qop == "" {
+		response = digest(ha1, c.nonce, ha2)
+	} else {
+		response = digest(ha1, c.nonce, nonceCount, cnonce, qop, ha2)
+	}
+
+	authorization := fmt.Sprintf(`Digest username="%s", realm="%s", nonce="%s", uri="%s", response="%s"`, username, c.realm, c.nonce, uri, response)
+	if c.algorithm != "" {
+		authorization += fmt.Sprintf(", algorithm=%s", c.algorithm)
+	}
+	if c.opaque != "" {
+		authorization += fmt.Sprintf(`, opaque="%s"`, c.opaque)
+	}
+	if qop != "" {
+		authorization += fmt.Sprintf(`, qop=%s, nc=%s, cnonce="%s"`, qop, nonceCount, cnonce)
+	}
+	return authorization, nil
+}
+
+func newActiveMQDigestCnonce() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// parseActiveMQDigestChallenge returns the digest challenge of the WWW-Authenticate headers, nil if there is none
+func parseActiveMQDigestChallenge(header http.Header) *activeMQDigestChallenge {
+	for _, value := range header.Values("WWW-Authenticate") {
+		if len(value) < len("Digest ") || !strings.EqualFold(value[:len("Digest ")], "Digest ") {
+			continue
+		}
+		params := parseActiveMQDigestParams(value[len("Digest "):])
+		if params["nonce"] == "" {
+			continue
+		}
+		challenge := &activeMQDigestChallenge{
+			realm:     params["realm"],
+			nonce:     params["nonce"],
+			opaque:    params["opaque"],
+			algorithm: params["algorithm"],
+		}
+		for _, qop := range strings.Split(params["qop"], ",") {
+			if qop = strings.TrimSpace(qop); qop != "" {
+				challenge.qop = append(challenge.qop, qop)
+			}
+		}
+		return challenge
+	}
+	return nil
+}
+
+// parseActiveMQDigestParams parses the comma separated key=value parameters of a challenge, values may be quoted
+func parseActiveMQDigestParams(value string) map[string]string {
+	params := map[string]string{}
+	for value != "" {
+		separator := strings.IndexByte(value, '=')
+		if separator < 0 {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(strings.TrimLeft(value[:separator], ", ")))
+		value = strings.TrimLeft(value[separator+1:], " ")
+
+		var param string
+		if strings.HasPrefix(value, `"`) {
+			var b strings.Builder
+			i := 1
+			for ; i < len(value) && value[i] != '"'; i++ {
+				if value[i] == '\\' && i+1 < len(value) {
+					i++
+				}
+				b.WriteByte(value[i])
+			}
+			param = b.String()
+			if i < len(value) {
+				i++
+			}
+			value = value[i:]
+		} else {
+			end := strings.IndexByte(value, ',')
+			if end < 0 {
+				end = len(value)
+			}
+			param = strings.TrimSpace(value[:end])
+			value = value[end:]
+		}
+		params[key] = param
+		value = strings.TrimLeft(value, ", ")
+	}
+	return params
+}
@@ -0,0 +1,182 @@
+package scalers
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseActiveMQDigestChallenge(t *testing.T) {
+	header := http.Header{}
+	header.Add("WWW-Authenticate", `Basic realm="activemq"`)
+	header.Add("WWW-Authenticate", `Digest realm="activemq, jolokia", qop="auth,auth-int", nonce="abc\"def", opaque=xyz, algorithm=MD5-sess`)
+
+	challenge := parseActiveMQDigestChallenge(header)
+	if challenge == nil {
+		t.Fatal("Expected a digest challenge")
+	}
+	if challenge.realm != "activemq, jolokia" || challenge.nonce != `abc"def` || challenge.opaque != "xyz" || challenge.algorithm != "MD5-sess" {
+		t.Errorf("Wrong challenge: %+v", challenge)
+	}
+	if strings.Join(challenge.qop, ";") != "auth;auth-int" {
+		t.Errorf("Wrong qop: %v", challenge.qop)
+	}
+
+	if parseActiveMQDigestChallenge(http.Header{"Www-Authenticate": []string{`Basic realm="activemq"`}}) != nil {
+		t.Error("Expected no digest challenge for basic authentication")
+	}
+
+	challenge = &activeMQDigestChallenge{realm: "activemq", nonce: "n", qop: []string{"auth-int"}}
+	if _, err := challenge.authorization("user", "pass", "GET", "/"); err == nil {
+		t.Error("Expected error for an unsupported qop")
+	}
+	challenge = &activeMQDigestChallenge{realm: "activemq", nonce: "n", algorithm: "SHA-512"}
+	if _, err := challenge.authorization("user", "pass", "GET", "/"); err == nil {
+		t.Error("Expected error for an unsupported algorithm")
+	}
+}
+
+// activeMQDigestStub is a management endpoint protected by HTTP digest authentication with MD5 and qop=auth
+type activeMQDigestStub struct {
+	lock       sync.Mutex
+	nonce      int
+	challenges int
+	reads      int
+	failures   int
+	bodies     []string
+}
+
+func (s *activeMQDigestStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	nonce := fmt.Sprintf("nonce-%d", s.nonce)
+	params := parseActiveMQDigestParams(strings.TrimPrefix(r.Header.Get("Authorization"), "Digest "))
+	digest := func(parts ...string) string {
+		sum := md5.Sum([]byte(strings.Join(parts, ":")))
+		return hex.EncodeToString(sum[:])
+	}
+	ha1 := digest("testUsername", "activemq", "pass123")
+	ha2 := digest(r.Method, r.URL.RequestURI())
+	if params["nonce"] != nonce || params["response"] != digest(ha1, nonce, params["nc"], params["cnonce"], "auth", ha2) {
+		s.challenges++
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Digest realm="activemq", qop="auth", nonce="%s", opaque="o"`, nonce))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if s.failures > 0 {
+		s.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	body, _ := ioutil.ReadAll(r.Body)
+	s.bodies = append(s.bodies, string(body))
+	s.reads++
+	_, _ = io.WriteString(w, `{"value":3,"timestamp":1644231160,"status":200}`)
+}
+
+func TestActiveMQDigestAuth(t *testing.T) {
+	stub := &activeMQDigestStub{}
+	apiStub := httptest.NewTLSServer(stub)
+	defer apiStub.Close()
+
+	newScaler := func(password string) *activeMQScaler {
+		scaler, err := NewActiveMQScaler(&ScalerConfig{
+			TriggerMetadata: map[string]string{
+				"managementEndpoint": strings.TrimPrefix(apiStub.URL, "https://"),
+				"unsafeSsl":          "true",
+				"destinationName":    "testQueue",
+				"brokerName":         "localhost",
+				"authMode":           "digest",
+				"retryCount":         "1",
+				"retryInterval":      "1",
+			},
+			AuthParams:        map[string]string{"username": "testUsername", "password": password},
+			GlobalHTTPTimeout: time.Second,
+		})
+		if err != nil {
+			t.Fatal("Could not create scaler:", err)
+		}
+		return scaler.(*activeMQScaler)
+	}
+
+	scaler := newScaler("pass123")
+	for i := 0; i < 3; i++ {
+		if _, err := scaler.getDestinationMetric(context.Background()); err != nil {
+			t.Fatalf("Poll %d: expected success but got error %s", i, err)
+		}
+	}
+	if stub.challenges != 1 || stub.reads != 3 {
+		t.Errorf("Expected the nonce to be reused: %d challenges for %d reads", stub.challenges, stub.reads)
+	}
+
+	// a new nonce and a failed read, the handshake is done again and the retry reuses the new nonce
+	stub.nonce++
+	stub.failures = 1
+	if _, err := scaler.getDestinationMetric(context.Background()); err != nil {
+		t.Fatal("Expected success but got error", err)
+	}
+	if stub.challenges != 2 || stub.reads != 4 {
+		t.Errorf("Expected one more handshake: %d challenges for %d reads", stub.challenges, stub.reads)
+	}
+
+	// the body of a POST is sent again after the challenge
+	stub.nonce++
+	req, err := http.NewRequest("POST", apiStub.URL+"/api/jolokia/", bytes.NewReader([]byte(`{"type":"read"}`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := scaler.httpClient.Do(req)
+	if err != nil {
+		t.Fatal("Expected success but got error", err)
+	}
+	resp.Body.Close()
+	if body := stub.bodies[len(stub.bodies)-1]; resp.StatusCode != http.StatusOK || body != `{"type":"read"}` {
+		t.Errorf("Expected the request body to be sent again, got status %d and body %q", resp.StatusCode, body)
+	}
+
+	_, err = newScaler("wrong").getDestinationMetric(context.Background())
+	if err == nil {
+		t.Fatal("Expected error but got success")
+	}
+	if !strings.Contains(err.Error(), "status 401") {
+		t.Errorf("Expected an authentication error but got %s", err)
+	}
+}
+
+func TestParseActiveMQDigestAuth(t *testing.T) {
+	testCases := []struct {
+		name       string
+		authParams map[string]string
+		isError    bool
+	}{
+		{"username and password", map[string]string{"username": "testUsername", "password": "pass123"}, false},
+		{"missing password", map[string]string{"username": "testUsername"}, true},
+		{"bearer token", map[string]string{"username": "testUsername", "password": "pass123", "bearerToken": "token"}, true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := parseActiveMQMetadata(&ScalerConfig{
+				TriggerMetadata: map[string]string{"managementEndpoint": "localhost:8161", "destinationName": "testQueue", "brokerName": "localhost", "authMode": "digest"},
+				AuthParams:      testCase.authParams,
+			})
+			if err != nil && !testCase.isError {
+				t.Error("Expected success but got error", err)
+			}
+			if testCase.isError && err == nil {
+				t.Error("Expected error but got success")
+			}
+		})
+	}
+}
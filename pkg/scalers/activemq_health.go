@@ -0,0 +1,59 @@
+package scalers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// HealthCheck pings the management endpoints with a Jolokia version request, or by connecting over STOMP, which
+// also checks the credentials. With failover a single reachable endpoint is enough, otherwise all must answer.
+func (s *activeMQScaler) HealthCheck(ctx context.Context) error {
+	var firstErr error
+	var errs []string
+	for _, endpoint := range s.metadata.managementEndpoints {
+		if err := s.pingEndpoint(ctx, endpoint); err != nil {
+			if firstErr == nil {
+				// the kind of the first failure, unreachable or rejected credentials, is kept for the caller
+				firstErr = fmt.Errorf("%s: %w", endpoint, err)
+				continue
+			}
+			errs = append(errs, fmt.Sprintf("; %s: %s", endpoint, err))
+			continue
+		}
+		if s.metadata.endpointSelection == activeMQFailoverEndpointSelection {
+			return nil
+		}
+	}
+	if firstErr != nil {
+		return fmt.Errorf("ActiveMQ management endpoints unhealthy: %w%s", firstErr, strings.Join(errs, ""))
+	}
+	return nil
+}
+
+// pingEndpoint checks that the management endpoint answers without reading any destination
+func (s *activeMQScaler) pingEndpoint(ctx context.Context, endpoint string) error {
+	if s.metadata.protocol == activeMQStompProtocol {
+		client, err := dialActiveMQStomp(ctx, endpoint, s.tlsConfig, s.metadata.username, s.metadata.password)
+		if err != nil {
+			if errors.Is(err, ErrAuth) {
+				return err
+			}
+			return newUnreachableError(err)
+		}
+		return client.Close()
+	}
+
+	var response *activeMQJolokiaResponse
+	if unreachable, err := s.postJolokia(ctx, endpoint, map[string]string{"type": "version"}, &response); err != nil {
+		if unreachable {
+			err = newUnreachableError(err)
+		}
+		return err
+	}
+	if !s.metadata.isSuccessStatus(response.Status) {
+		return fmt.Errorf("Jolokia version request failed with status %d: %s", response.Status, response.Error)
+	}
+	return nil
+}
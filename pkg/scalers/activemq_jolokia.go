@@ -0,0 +1,936 @@
+package scalers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/kedacore/keda/v2/pkg/scalers/authentication"
+)
+
+// activeMQJolokiaFetcher reads the samples with Jolokia requests, for all the broker types and the proxy mode
+type activeMQJolokiaFetcher struct {
+	scaler *activeMQScaler
+}
+
+// Fetch reads the sample with a Jolokia read or bulk request
+func (f *activeMQJolokiaFetcher) Fetch(ctx context.Context, endpoint string) (activeMQSample, bool, error) {
+	return f.scaler.getJolokiaSample(ctx, endpoint)
+}
+
+// activeMQDestination is one entry of the broker's Queues or Topics attribute
+type activeMQDestination struct {
+	ObjectName string `json:"objectName"`
+}
+
+// activeMQBulkRead is one read of a Jolokia POST request, alone or in a bulk request
+type activeMQBulkRead struct {
+	Type      string                 `json:"type"`
+	MBean     string                 `json:"mbean"`
+	Attribute string                 `json:"attribute,omitempty"`
+	Target    *activeMQJolokiaTarget `json:"target,omitempty"`
+}
+
+// activeMQJolokiaTarget is the remote JMX service a Jolokia agent in proxy mode forwards a read to
+type activeMQJolokiaTarget struct {
+	URL      string `json:"url"`
+	User     string `json:"user,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// activeMQJolokiaResponse is the response to a Jolokia read, the value is decoded once the shape of the read attribute is known
+type activeMQJolokiaResponse struct {
+	Value     json.RawMessage `json:"value"`
+	Status    int             `json:"status"`
+	Error     string          `json:"error"`
+	ErrorType string          `json:"error_type"`
+	Timestamp int64           `json:"timestamp"`
+	raw       json.RawMessage // the whole response, for valueJSONPath
+}
+
+// UnmarshalJSON decodes the response, keeping it whole so that the value can be selected with valueJSONPath
+func (r *activeMQJolokiaResponse) UnmarshalJSON(data []byte) error {
+	type response activeMQJolokiaResponse
+	if err := json.Unmarshal(data, (*response)(r)); err != nil {
+		return err
+	}
+	r.raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// selectValue returns the response with the value found at the gjson path, such as value.QueueSize, in place of
+// the value field
+func (r *activeMQJolokiaResponse) selectValue(path string) (*activeMQJolokiaResponse, error) {
+	result := gjson.GetBytes(r.raw, path)
+	if !result.Exists() {
+		return nil, fmt.Errorf("valueJSONPath %s not found in the ActiveMQ management endpoint response %s", path, activeMQResponseSnippet(r.raw))
+	}
+	selected := *r
+	selected.Value = json.RawMessage(result.Raw)
+	return &selected, nil
+}
+
+// isSuccessStatus reports whether the Jolokia status of a response is one of the successStatusCodes
+func (m *activeMQMetadata) isSuccessStatus(status int) bool {
+	for _, code := range m.successStatusCodes {
+		if status == code {
+			return true
+		}
+	}
+	return false
+}
+
+// instanceNotFound reports whether the read failed because the MBean is not registered, which is the case for a
+// destination that has not been created yet. Other failures, such as an unknown attribute, are not reported.
+func (r *activeMQJolokiaResponse) instanceNotFound() bool {
+	return r.Status == http.StatusNotFound && r.ErrorType == activeMQInstanceNotFoundErrorType
+}
+
+// number returns the value of a numeric attribute. Depending on the broker and Jolokia version it is serialized
+// as an integer, a float or a string.
+func (r *activeMQJolokiaResponse) number() (float64, error) {
+	var number json.Number
+	if err := json.Unmarshal(r.Value, &number); err != nil || number == "" {
+		return 0, fmt.Errorf("invalid value %s returned by the ActiveMQ management endpoint", r.Value)
+	}
+	value, err := number.Float64()
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %s returned by the ActiveMQ management endpoint", r.Value)
+	}
+	return value, nil
+}
+
+// destinations returns the value of the broker's Queues or Topics attribute
+func (r *activeMQJolokiaResponse) destinations() ([]activeMQDestination, error) {
+	var destinations []activeMQDestination
+	if err := json.Unmarshal(r.Value, &destinations); err != nil {
+		return nil, fmt.Errorf("invalid destinations %s returned by the ActiveMQ management endpoint", r.Value)
+	}
+	return destinations, nil
+}
+
+// getRestAPIParameters parse restAPITemplate to provide managementEndpoint, brokerName, destinationName, destinationType
+// and detects the broker type from the MBean domain of the template
+func getRestAPIParameters(meta activeMQMetadata) (activeMQMetadata, error) {
+	u, err := url.ParseRequestURI(meta.restAPITemplate)
+	if err != nil {
+		return meta, fmt.Errorf("unable to parse ActiveMQ restAPITemplate: %s", err)
+	}
+
+	meta.managementEndpoint = u.Host
+	meta.managementEndpoints = []string{u.Host}
+	meta.scheme = u.Scheme
+	splitPath := strings.Split(u.Path, ":")
+	if len(splitPath) < 2 {
+		return meta, fmt.Errorf("restAPITemplate must read an MBean such as org.apache.activemq:type=Broker,brokerName=<<brokerName>>,destinationType=Queue,destinationName=<<destinationName>>: %s", meta.restAPITemplate)
+	}
+	domain := splitPath[0][strings.LastIndex(splitPath[0], "/")+1:] // This returns : org.apache.activemq or org.apache.activemq.artemis
+	splitURL := strings.Split(splitPath[1], "/")[0]                 // This returns : type=Broker,brokerName=<<brokerName>>,destinationType=Queue,destinationName=<<destinationName>>
+	replacer := strings.NewReplacer(",", "&")
+	v, err := url.ParseQuery(replacer.Replace(splitURL)) // This returns a map with key: string types and element type [] string. : map[brokerName:[<<brokerName>>] destinationName:[<<destinationName>>] destinationType:[Queue] type:[Broker]]
+	if err != nil {
+		return meta, fmt.Errorf("unable to parse ActiveMQ restAPITemplate: %s", err)
+	}
+
+	// anything in front of the Jolokia path is kept as prefix, e.g. when Jolokia is served behind a reverse proxy
+	jolokiaPath := activeMQJolokiaPath
+	if domain == activeMQArtemisMBeanDomain {
+		jolokiaPath = artemisJolokiaPath
+	}
+	if i := strings.Index(u.Path, jolokiaPath); i > 0 {
+		meta.jolokiaPathPrefix = u.Path[:i]
+	}
+
+	if domain == activeMQArtemisMBeanDomain {
+		return getArtemisRestAPIParameters(meta, v)
+	}
+	meta.brokerType = activeMQClassicBrokerType
+
+	// the keys may be missing altogether, not only empty
+	meta.destinationName = v.Get("destinationName")
+	if meta.destinationName == "" {
+		return meta, fmt.Errorf("no destinationName given in restAPITemplate, expected destinationName=<<destinationName>> in the MBean name: %s", meta.restAPITemplate)
+	}
+
+	meta.brokerName = v.Get("brokerName")
+	if meta.brokerName == "" {
+		return meta, fmt.Errorf("no brokerName given in restAPITemplate, expected brokerName=<<brokerName>> in the MBean name: %s", meta.restAPITemplate)
+	}
+
+	meta.destinationType = defaultActiveMQDestinationType
+	if destinationType := v.Get("destinationType"); destinationType != "" {
+		if err := validateActiveMQDestinationType(destinationType); err != nil {
+			return meta, err
+		}
+		meta.destinationType = destinationType
+	}
+
+	return meta, nil
+}
+
+// getArtemisRestAPIParameters reads the Artemis MBean layout: broker="<<brokerName>>",component=addresses,address="<<address>>"[,subcomponent=queues,routing-type="anycast",queue="<<queueName>>"]
+func getArtemisRestAPIParameters(meta activeMQMetadata, v url.Values) (activeMQMetadata, error) {
+	meta.brokerType = activeMQArtemisBrokerType
+
+	meta.brokerName = strings.Trim(v.Get("broker"), `"`)
+	if meta.brokerName == "" {
+		return meta, fmt.Errorf("no broker given: %s", meta.restAPITemplate)
+	}
+
+	meta.brokerAddress = strings.Trim(v.Get("address"), `"`)
+	if meta.brokerAddress == "" {
+		return meta, fmt.Errorf("no address given: %s", meta.restAPITemplate)
+	}
+
+	// Without a queue the template targets the address itself, which is how Artemis models a topic
+	if queue := strings.Trim(v.Get("queue"), `"`); queue != "" {
+		meta.destinationName = queue
+		meta.destinationType = activeMQQueueDestinationType
+	} else {
+		meta.destinationName = meta.brokerAddress
+		meta.destinationType = activeMQTopicDestinationType
+	}
+
+	return meta, nil
+}
+
+// getJolokiaAttribute returns the name of the configured target attribute on the broker's MBean
+func (s *activeMQScaler) getJolokiaAttribute() string {
+	if s.metadata.brokerUsage != nil {
+		return s.metadata.brokerUsage.attribute
+	}
+	if s.metadata.subscriptionName != "" {
+		return "PendingQueueSize"
+	}
+	if s.metadata.brokerType == activeMQArtemisBrokerType {
+		return activeMQAttributes[s.metadata.targetAttribute].artemisName
+	}
+	return s.metadata.targetAttribute
+}
+
+// getMonitoringTemplate returns the default Jolokia read template for the configured broker usage, or broker and destination type
+func (s *activeMQScaler) getMonitoringTemplate() string {
+	if s.metadata.brokerUsage != nil {
+		return defaultActiveMQBrokerRestAPITemplate
+	}
+	if s.metadata.subscriptionName != "" {
+		return defaultActiveMQSubscriptionRestAPITemplate
+	}
+	if s.metadata.brokerType != activeMQArtemisBrokerType {
+		return defaultActiveMQRestAPITemplate
+	}
+	if s.metadata.destinationType == activeMQTopicDestinationType {
+		return defaultArtemisTopicRestAPITemplate
+	}
+	return defaultArtemisQueueRestAPITemplate
+}
+
+// getMBean returns the name of the MBean read by getMonitoringTemplate, for reads sent in a POST body
+func (s *activeMQScaler) getMBean(destinationName string) string {
+	switch {
+	case s.metadata.brokerUsage != nil:
+		return fmt.Sprintf("org.apache.activemq:type=Broker,brokerName=%s", s.brokerName())
+	case s.metadata.subscriptionName != "":
+		return fmt.Sprintf("org.apache.activemq:type=Broker,brokerName=%s,destinationType=Topic,destinationName=%s,endpoint=Consumer,clientId=%s,consumerId=Durable(%s_%s)",
+			s.brokerName(), destinationName, s.metadata.subscriptionClientID, s.metadata.subscriptionClientID, s.metadata.subscriptionName)
+	case s.metadata.brokerType != activeMQArtemisBrokerType:
+		return fmt.Sprintf("org.apache.activemq:type=Broker,brokerName=%s,destinationType=%s,destinationName=%s", s.brokerName(), s.metadata.destinationType, destinationName)
+	case s.metadata.destinationType == activeMQTopicDestinationType:
+		return fmt.Sprintf(`org.apache.activemq.artemis:broker="%s",component=addresses,address="%s"`, s.brokerName(), s.metadata.brokerAddress)
+	default:
+		return fmt.Sprintf(`org.apache.activemq.artemis:broker="%s",component=addresses,address="%s",subcomponent=queues,routing-type="anycast",queue="%s"`, s.brokerName(), s.metadata.brokerAddress, destinationName)
+	}
+}
+
+// getMBeanAttribute returns the attribute read by getMonitoringTemplate, the Artemis topic template reads MessageCount
+func (s *activeMQScaler) getMBeanAttribute() string {
+	if s.metadata.brokerUsage == nil && s.metadata.brokerType == activeMQArtemisBrokerType && s.metadata.destinationType == activeMQTopicDestinationType {
+		return "MessageCount"
+	}
+	return s.getJolokiaAttribute()
+}
+
+// newJolokiaRead returns a read of the MBean attribute, forwarded to the remote JMX service in proxy mode
+func (s *activeMQScaler) newJolokiaRead(mbean, attribute string) activeMQBulkRead {
+	return activeMQBulkRead{
+		Type:      "read",
+		MBean:     mbean,
+		Attribute: attribute,
+		Target:    s.metadata.jolokiaProxyTarget,
+	}
+}
+
+func (s *activeMQScaler) getMonitoringEndpoint(managementEndpoint, destinationName string) (string, error) {
+	return s.executeTemplate(s.getMonitoringTemplate(), managementEndpoint, destinationName)
+}
+
+// getDestinationsEndpoint returns the Jolokia read of the broker attribute listing its queues or topics
+func (s *activeMQScaler) getDestinationsEndpoint(managementEndpoint string) (string, error) {
+	return s.executeTemplate(defaultActiveMQDestinationsRestAPITemplate, managementEndpoint, "")
+}
+
+func (s *activeMQScaler) executeTemplate(text, managementEndpoint, destinationName string) (string, error) {
+	var buf bytes.Buffer
+	endpoint := map[string]string{
+		"ManagementEndpoint": managementEndpoint,
+		"BrokerName":         s.brokerName(),
+		"DestinationName":    destinationName,
+		"DestinationType":    s.metadata.destinationType,
+		"BrokerAddress":      s.metadata.brokerAddress,
+		"Scheme":             s.metadata.scheme,
+		"PathPrefix":         s.metadata.jolokiaPathPrefix,
+		"Attribute":          s.getJolokiaAttribute(),
+		"ClientID":           s.metadata.subscriptionClientID,
+		"SubscriptionName":   s.metadata.subscriptionName,
+	}
+	template, err := template.New("monitoring_endpoint").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("error parsing template: %s", err)
+	}
+	err = template.Execute(&buf, endpoint)
+	if err != nil {
+		return "", fmt.Errorf("error executing template: %s", err)
+	}
+	monitoringEndpoint := buf.String()
+	return monitoringEndpoint, nil
+}
+
+// getJolokiaSample reads the target attribute of the destination from one management endpoint. When
+// destinationName is a pattern, the attribute is summed over all the broker's destinations matching it,
+// when it is a weighted list, over the listed destinations multiplied by their weight.
+// It reports whether a failure means the endpoint is unreachable.
+func (s *activeMQScaler) getJolokiaSample(ctx context.Context, endpoint string) (activeMQSample, bool, error) {
+	s.detectJolokiaVersion(ctx, endpoint)
+
+	destinations := []string{s.metadata.destinationName}
+	weights := []float64{1}
+	if s.metadata.weightedDestinations != nil {
+		destinations, weights = nil, nil
+		for _, destination := range s.metadata.weightedDestinations {
+			destinations = append(destinations, destination.name)
+			weights = append(weights, destination.weight)
+		}
+	}
+	if s.metadata.destinationPattern != nil {
+		var unreachable bool
+		var err error
+		if destinations, unreachable, err = s.getMatchingDestinations(ctx, endpoint); err != nil {
+			return activeMQSample{}, unreachable, err
+		}
+		weights = make([]float64, len(destinations))
+		for i := range weights {
+			weights[i] = 1
+		}
+		if len(destinations) == 0 {
+			activeMQLog.V(1).Info("No ActiveMQ destination matches the destinationName pattern", "managementEndpoint", endpoint, "destinationName", s.metadata.destinationName)
+			return activeMQSample{value: 0, timestamp: time.Now().Unix()}, false, nil
+		}
+	}
+
+	if s.metadata.metricExpression != nil {
+		return s.getExpressionSample(ctx, endpoint, destinations[0])
+	}
+	if s.metadata.networkConnector != "" {
+		return s.getNetworkConnectorSample(ctx, endpoint)
+	}
+
+	var monitoringInfos []*activeMQJolokiaResponse
+	unreachable, err := s.withRetries(ctx, endpoint, func() (bool, error) {
+		var retryable bool
+		var err error
+		if len(destinations) == 1 {
+			var monitoringInfo *activeMQJolokiaResponse
+			monitoringInfo, retryable, err = s.readMonitoringInfo(ctx, endpoint, destinations[0])
+			monitoringInfos = []*activeMQJolokiaResponse{monitoringInfo}
+		} else {
+			// read all the destinations in one round-trip
+			monitoringInfos, retryable, err = s.readBulkMonitoringInfo(ctx, endpoint, destinations)
+		}
+		return retryable, err
+	})
+	if err != nil {
+		return activeMQSample{}, unreachable, err
+	}
+
+	samples := make([]activeMQSample, 0, len(monitoringInfos))
+	for i, monitoringInfo := range monitoringInfos {
+		timestamp := monitoringInfo.Timestamp
+		if timestamp == 0 {
+			timestamp = time.Now().Unix()
+		}
+		if monitoringInfo.instanceNotFound() {
+			// only returned with treatMissingAsZero, the destination has not been created yet
+			activeMQLog.V(1).Info("ActiveMQ destination not found, counting it as empty", "managementEndpoint", endpoint, "destinationName", destinations[i])
+			samples = append(samples, activeMQSample{value: 0, timestamp: timestamp})
+			continue
+		}
+		if s.metadata.valueJSONPath != "" {
+			if monitoringInfo, err = monitoringInfo.selectValue(s.metadata.valueJSONPath); err != nil {
+				return activeMQSample{}, false, err
+			}
+		}
+		value, err := attributeValue(monitoringInfo, s.metadata.targetAttribute)
+		if err != nil {
+			s.logUndecodableResponse(endpoint, monitoringInfo.raw, err)
+			return activeMQSample{}, false, err
+		}
+		samples = append(samples, activeMQSample{value: value * weights[i], timestamp: timestamp})
+	}
+	if activeMQAttributes[s.metadata.targetAttribute].age {
+		return aggregateActiveMQSamples(samples, activeMQMaxAggregation), false, nil
+	}
+	return aggregateActiveMQSamples(samples, activeMQSumAggregation), false, nil
+}
+
+// getExpressionSample reads all the attributes of the metricExpression with a single Jolokia bulk request and evaluates it
+func (s *activeMQScaler) getExpressionSample(ctx context.Context, endpoint, destinationName string) (activeMQSample, bool, error) {
+	attributes := s.metadata.metricExpression.attributes
+	requests := make([]activeMQBulkRead, 0, len(attributes))
+	for _, attribute := range attributes {
+		name := attribute
+		if s.metadata.brokerType == activeMQArtemisBrokerType {
+			name = activeMQAttributes[attribute].artemisName
+		}
+		requests = append(requests, s.newJolokiaRead(s.getMBean(destinationName), name))
+	}
+
+	var responses []*activeMQJolokiaResponse
+	unreachable, err := s.withRetries(ctx, endpoint, func() (bool, error) {
+		responses = nil
+		return s.postJolokia(ctx, endpoint, requests, &responses)
+	})
+	if err != nil {
+		return activeMQSample{}, unreachable, err
+	}
+	if len(responses) != len(attributes) {
+		return activeMQSample{}, false, fmt.Errorf("ActiveMQ management endpoint returned %d responses for %d bulk reads", len(responses), len(attributes))
+	}
+
+	values := make(map[string]float64, len(attributes))
+	var timestamp int64
+	for i, response := range responses {
+		if s.metadata.treatMissingAsZero && response.instanceNotFound() {
+			activeMQLog.V(1).Info("ActiveMQ destination not found, counting it as empty", "managementEndpoint", endpoint, "destinationName", destinationName)
+			return activeMQSample{value: 0, timestamp: time.Now().Unix()}, false, nil
+		}
+		if !s.metadata.isSuccessStatus(response.Status) {
+			return activeMQSample{}, false, fmt.Errorf("Jolokia read of the ActiveMQ attribute %s failed with status %d: %s", attributes[i], response.Status, response.Error)
+		}
+		value, err := response.number()
+		if err != nil {
+			return activeMQSample{}, false, err
+		}
+		values[attributes[i]] = value
+		if response.Timestamp > timestamp {
+			timestamp = response.Timestamp
+		}
+	}
+	if timestamp == 0 {
+		timestamp = time.Now().Unix()
+	}
+	value := s.metadata.metricExpression.eval(values)
+	if s.metadata.messagesPerConsumer {
+		value = math.Ceil(value)
+	}
+	return activeMQSample{value: value, timestamp: timestamp}, false, nil
+}
+
+// getNetworkConnectorSample reads the enqueue and dequeue counters of all the bridges of the network connector with a
+// single pattern read, and returns the messages taken from the local broker but not forwarded yet. Without bridge,
+// e.g. while the remote broker is down, nothing is pending.
+func (s *activeMQScaler) getNetworkConnectorSample(ctx context.Context, endpoint string) (activeMQSample, bool, error) {
+	read := struct {
+		Type      string                 `json:"type"`
+		MBean     string                 `json:"mbean"`
+		Attribute []string               `json:"attribute"`
+		Target    *activeMQJolokiaTarget `json:"target,omitempty"`
+	}{
+		Type:      "read",
+		MBean:     fmt.Sprintf("org.apache.activemq:type=Broker,brokerName=%s,connector=networkConnectors,networkConnectorName=%s,networkBridge=*", s.brokerName(), s.metadata.networkConnector),
+		Attribute: []string{"EnqueueCounter", "DequeueCounter"},
+		Target:    s.metadata.jolokiaProxyTarget,
+	}
+	var response *activeMQJolokiaResponse
+	unreachable, err := s.withRetries(ctx, endpoint, func() (bool, error) {
+		response = nil
+		return s.postJolokia(ctx, endpoint, read, &response)
+	})
+	if err != nil {
+		return activeMQSample{}, unreachable, err
+	}
+	timestamp := response.Timestamp
+	if timestamp == 0 {
+		timestamp = time.Now().Unix()
+	}
+	if response.instanceNotFound() {
+		return activeMQSample{value: 0, timestamp: timestamp}, false, nil
+	}
+	if !s.metadata.isSuccessStatus(response.Status) {
+		return activeMQSample{}, false, fmt.Errorf("Jolokia read of the ActiveMQ network connector %s failed with status %d: %s", s.metadata.networkConnector, response.Status, response.Error)
+	}
+
+	var bridges map[string]struct {
+		EnqueueCounter float64
+		DequeueCounter float64
+	}
+	if err := json.Unmarshal(response.Value, &bridges); err != nil {
+		s.logUndecodableResponse(endpoint, response.raw, err)
+		return activeMQSample{}, false, fmt.Errorf("unexpected value of the ActiveMQ network connector %s: %s", s.metadata.networkConnector, err)
+	}
+	pending := 0.0
+	for _, bridge := range bridges {
+		if bridge.EnqueueCounter > bridge.DequeueCounter {
+			pending += bridge.EnqueueCounter - bridge.DequeueCounter
+		}
+	}
+	return activeMQSample{value: pending, timestamp: timestamp}, false, nil
+}
+
+// discoverBrokerName searches the broker MBeans of the management endpoint when brokerName is left out of the
+// trigger, the name found is kept for the following polls. Several brokers are an error.
+func (s *activeMQScaler) discoverBrokerName(ctx context.Context) (bool, error) {
+	s.discoveryLock.Lock()
+	defer s.discoveryLock.Unlock()
+	if s.brokerName() != "" {
+		return false, nil
+	}
+
+	endpoint := s.metadata.managementEndpoints[0]
+	search := activeMQBulkRead{Type: "search", MBean: activeMQBrokerSearchPattern, Target: s.metadata.jolokiaProxyTarget}
+	var response *activeMQJolokiaResponse
+	unreachable, err := s.withRetries(ctx, endpoint, func() (bool, error) {
+		response = nil
+		return s.postJolokia(ctx, endpoint, search, &response)
+	})
+	if err != nil {
+		return unreachable, fmt.Errorf("error searching the ActiveMQ broker: %s", err)
+	}
+	if !s.metadata.isSuccessStatus(response.Status) {
+		return false, fmt.Errorf("Jolokia search of the ActiveMQ broker failed with status %d: %s", response.Status, response.Error)
+	}
+	var objectNames []string
+	if err := json.Unmarshal(response.Value, &objectNames); err != nil {
+		return false, fmt.Errorf("unexpected Jolokia search response %s: %s", activeMQResponseSnippet(response.Value), err)
+	}
+
+	var brokerNames []string
+	found := make(map[string]bool)
+	for _, objectName := range objectNames {
+		if name := parseActiveMQObjectName(objectName)["brokerName"]; name != "" && !found[name] {
+			found[name] = true
+			brokerNames = append(brokerNames, name)
+		}
+	}
+	switch len(brokerNames) {
+	case 0:
+		return false, fmt.Errorf("no ActiveMQ broker found on %s, brokerName must be given", endpoint)
+	case 1:
+		s.brokerLock.Lock()
+		s.discoveredBrokerName = brokerNames[0]
+		s.brokerLock.Unlock()
+		activeMQLog.V(1).Info("Discovered the ActiveMQ broker", "managementEndpoint", endpoint, "brokerName", brokerNames[0])
+		return false, nil
+	default:
+		sort.Strings(brokerNames)
+		return false, fmt.Errorf("several ActiveMQ brokers found on %s (%s), brokerName must be given", endpoint, strings.Join(brokerNames, ", "))
+	}
+}
+
+// getMatchingDestinations lists the broker's destinations of the configured type and returns those matching destinationName
+func (s *activeMQScaler) getMatchingDestinations(ctx context.Context, endpoint string) ([]string, bool, error) {
+	var response *activeMQJolokiaResponse
+	unreachable, err := s.withRetries(ctx, endpoint, func() (bool, error) {
+		response = nil
+		if s.metadata.jolokiaProxyTarget != nil {
+			read := s.newJolokiaRead(fmt.Sprintf("org.apache.activemq:type=Broker,brokerName=%s", s.brokerName()), s.metadata.destinationType+"s")
+			return s.postJolokia(ctx, endpoint, read, &response)
+		}
+		url, err := s.getDestinationsEndpoint(endpoint)
+		if err != nil {
+			return false, err
+		}
+		return s.readJolokia(ctx, endpoint, "GET", url, nil, &response)
+	})
+	if err != nil {
+		return nil, unreachable, err
+	}
+	if !s.metadata.isSuccessStatus(response.Status) {
+		return nil, false, fmt.Errorf("Jolokia read of the ActiveMQ destinations failed with status %d: %s", response.Status, response.Error)
+	}
+	destinations, err := response.destinations()
+	if err != nil {
+		return nil, false, err
+	}
+
+	var matching []string
+	for _, destination := range destinations {
+		name := parseActiveMQObjectName(destination.ObjectName)["destinationName"]
+		if name != "" && s.metadata.destinationPattern.MatchString(name) {
+			matching = append(matching, name)
+		}
+	}
+	return matching, false, nil
+}
+
+// parseActiveMQObjectName returns the key properties of a JMX object name such as
+// org.apache.activemq:brokerName=localhost,destinationName=foo,destinationType=Queue,type=Broker
+func parseActiveMQObjectName(objectName string) map[string]string {
+	properties := make(map[string]string)
+	if i := strings.Index(objectName, ":"); i >= 0 {
+		objectName = objectName[i+1:]
+	}
+	for _, property := range strings.Split(objectName, ",") {
+		if kv := strings.SplitN(property, "=", 2); len(kv) == 2 {
+			properties[kv[0]] = kv[1]
+		}
+	}
+	return properties
+}
+
+// attributeValue returns the value read for a target attribute, converting message ages to seconds.
+// Artemis reports no age for an empty queue, which has no waiting message.
+func attributeValue(response *activeMQJolokiaResponse, attribute string) (float64, error) {
+	if !activeMQAttributes[attribute].age {
+		return response.number()
+	}
+	if string(response.Value) == "null" {
+		return 0, nil
+	}
+	value, err := response.number()
+	if err != nil {
+		return 0, err
+	}
+	return value / 1000, nil
+}
+
+// readMonitoringInfo performs a single Jolokia read of the destination's target attribute, reporting whether a failure is worth retrying
+func (s *activeMQScaler) readMonitoringInfo(ctx context.Context, endpoint, destinationName string) (*activeMQJolokiaResponse, bool, error) {
+	var monitoringInfo *activeMQJolokiaResponse
+
+	if s.metadata.jolokiaProxyTarget != nil {
+		// a proxied read can only be expressed in a POST body
+		read := s.newJolokiaRead(s.getMBean(destinationName), s.getMBeanAttribute())
+		if retryable, err := s.postJolokia(ctx, endpoint, read, &monitoringInfo); err != nil {
+			return nil, retryable, err
+		}
+	} else {
+		url, err := s.getMonitoringEndpoint(endpoint, destinationName)
+		if err != nil {
+			return nil, false, err
+		}
+		if retryable, err := s.readJolokia(ctx, endpoint, "GET", url, nil, &monitoringInfo); err != nil {
+			return nil, retryable, err
+		}
+	}
+	if s.metadata.treatMissingAsZero && monitoringInfo.instanceNotFound() {
+		return monitoringInfo, false, nil
+	}
+	if !s.metadata.isSuccessStatus(monitoringInfo.Status) {
+		return nil, false, fmt.Errorf("Jolokia read of the ActiveMQ destination failed with status %d: %s", monitoringInfo.Status, monitoringInfo.Error)
+	}
+
+	return monitoringInfo, false, nil
+}
+
+// readBulkMonitoringInfo reads the target attribute of several destinations with a single Jolokia bulk request,
+// reporting whether a failure is worth retrying
+func (s *activeMQScaler) readBulkMonitoringInfo(ctx context.Context, endpoint string, destinationNames []string) ([]*activeMQJolokiaResponse, bool, error) {
+	var monitoringInfos []*activeMQJolokiaResponse
+
+	requests := make([]activeMQBulkRead, 0, len(destinationNames))
+	for _, destinationName := range destinationNames {
+		requests = append(requests, s.newJolokiaRead(s.getMBean(destinationName), s.getMBeanAttribute()))
+	}
+
+	if retryable, err := s.postJolokia(ctx, endpoint, requests, &monitoringInfos); err != nil {
+		return nil, retryable, err
+	}
+	if len(monitoringInfos) != len(destinationNames) {
+		return nil, false, fmt.Errorf("ActiveMQ management endpoint returned %d responses for %d bulk reads", len(monitoringInfos), len(destinationNames))
+	}
+	for i, monitoringInfo := range monitoringInfos {
+		if s.metadata.treatMissingAsZero && monitoringInfo.instanceNotFound() {
+			continue
+		}
+		if !s.metadata.isSuccessStatus(monitoringInfo.Status) {
+			return nil, false, fmt.Errorf("Jolokia read of the ActiveMQ destination %s failed with status %d: %s", destinationNames[i], monitoringInfo.Status, monitoringInfo.Error)
+		}
+	}
+
+	return monitoringInfos, false, nil
+}
+
+// getJolokiaBaseTemplate returns the template of the Jolokia agent URL, requests such as version are appended to it
+func (s *activeMQScaler) getJolokiaBaseTemplate() string {
+	switch {
+	case s.metadata.jolokiaProxyTarget != nil:
+		return defaultJolokiaProxyRestAPITemplate
+	case s.metadata.brokerType == activeMQArtemisBrokerType:
+		return defaultArtemisBulkRestAPITemplate
+	default:
+		return defaultActiveMQBulkRestAPITemplate
+	}
+}
+
+// getJolokiaVersion returns the configured or detected major version of the Jolokia agent, 0 when it is unknown
+func (s *activeMQScaler) getJolokiaVersion() int {
+	if s.metadata.jolokiaVersion != 0 {
+		return s.metadata.jolokiaVersion
+	}
+	s.versionLock.Lock()
+	defer s.versionLock.Unlock()
+	return s.detectedVersion
+}
+
+// detectJolokiaVersion probes the Jolokia agent for its version with jolokiaVersion auto, unless an earlier probe
+// succeeded. A failed probe is logged and retried on the next poll, the responses are decoded as Jolokia 1.x
+// ones meanwhile.
+func (s *activeMQScaler) detectJolokiaVersion(ctx context.Context, endpoint string) {
+	if !s.metadata.detectJolokiaVersion {
+		return
+	}
+	s.versionLock.Lock()
+	defer s.versionLock.Unlock()
+	if s.detectedVersion != 0 {
+		return
+	}
+
+	version, err := s.probeJolokiaVersion(ctx, endpoint)
+	if err != nil {
+		activeMQLog.Error(err, "Unable to detect the Jolokia version of the ActiveMQ management endpoint", "managementEndpoint", endpoint)
+		return
+	}
+	activeMQLog.V(1).Info("Detected the Jolokia version of the ActiveMQ management endpoint", "managementEndpoint", endpoint, "jolokiaVersion", version)
+	s.detectedVersion = version
+}
+
+// probeJolokiaVersion reads the version of the Jolokia agent and returns its major version
+func (s *activeMQScaler) probeJolokiaVersion(ctx context.Context, endpoint string) (int, error) {
+	url, err := s.executeTemplate(s.getJolokiaBaseTemplate()+"version", endpoint, "")
+	if err != nil {
+		return 0, err
+	}
+	var response *activeMQJolokiaResponse
+	if _, err := s.readJolokia(ctx, endpoint, "GET", url, nil, &response); err != nil {
+		return 0, err
+	}
+	if !s.metadata.isSuccessStatus(response.Status) {
+		return 0, fmt.Errorf("Jolokia version request failed with status %d: %s", response.Status, response.Error)
+	}
+	var version struct {
+		Agent string `json:"agent"`
+	}
+	if err := json.Unmarshal(response.Value, &version); err != nil || version.Agent == "" {
+		return 0, fmt.Errorf("invalid version %s returned by the ActiveMQ management endpoint", response.Value)
+	}
+	major, err := strconv.Atoi(strings.SplitN(version.Agent, ".", 2)[0])
+	if err != nil || major <= 0 {
+		return 0, fmt.Errorf("invalid Jolokia agent version %q returned by the ActiveMQ management endpoint", version.Agent)
+	}
+	return major, nil
+}
+
+// postJolokia sends the reads in a POST body, to the proxy agent when jolokiaProxyTarget is set, reporting whether a failure is worth retrying
+func (s *activeMQScaler) postJolokia(ctx context.Context, endpoint string, request, response interface{}) (bool, error) {
+	url, err := s.executeTemplate(s.getJolokiaBaseTemplate(), endpoint, "")
+	if err != nil {
+		return false, err
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return false, err
+	}
+	return s.readJolokia(ctx, endpoint, "POST", url, bytes.NewReader(body), response)
+}
+
+// readJolokia performs a single Jolokia request and decodes the response, reporting whether a failure is worth retrying
+func (s *activeMQScaler) readJolokia(ctx context.Context, endpoint, method, url string, body io.Reader, response interface{}) (bool, error) {
+	client := s.httpClient
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return false, err
+	}
+
+	// Add HTTP Auth and Headers
+	switch s.metadata.authMode {
+	case authentication.BearerAuthType:
+		token := s.metadata.bearerToken
+		if s.metadata.tokenFile != "" {
+			if token, err = readActiveMQTokenFile(s.metadata.tokenFile); err != nil {
+				return false, err
+			}
+		}
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	case activeMQOAuthAuthMode:
+		token, err := s.tokenManager.getToken(ctx)
+		if err != nil {
+			return false, err
+		}
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	case activeMQSessionAuthMode:
+		// the session cookie is added by the jar of the client
+		if err := s.sessionManager.login(ctx); err != nil {
+			return false, err
+		}
+	case activeMQDigestAuthMode, activeMQAWSSigV4AuthMode:
+		// the Authorization header is added by the digest or signing transport of the client
+	default:
+		req.SetBasicAuth(s.metadata.username, s.metadata.password)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", activeMQUserAgent)
+	if requestID, ok := ctx.Value(activeMQRequestIDKey{}).(string); ok {
+		req.Header.Set(activeMQRequestIDHeader, requestID)
+	}
+	if s.metadata.brokerType == activeMQArtemisBrokerType {
+		// Artemis' Jolokia rejects requests without an Origin allowed by its CORS policy
+		req.Header.Set("Origin", fmt.Sprintf(activeMQArtemisCorsTemplate, s.metadata.scheme, endpoint))
+	}
+	// custom headers are applied last so they can override the defaults above, e.g. the Origin
+	for key, value := range s.metadata.customHeaders {
+		req.Header.Set(key, value)
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		// the context ending is final, anything else at this point is a connection error
+		return ctx.Err() == nil, s.describeRequestError(ctx, endpoint, start, err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized && s.tokenManager != nil {
+		// the token may have been revoked, fetch a new one on the next poll
+		s.tokenManager.invalidate()
+	}
+	if resp.StatusCode == http.StatusUnauthorized && s.sessionManager != nil {
+		// the session may have expired, log in again on the next poll
+		s.sessionManager.invalidate()
+	}
+	respBody, err := readActiveMQResponseBody(resp)
+	if err != nil {
+		return ctx.Err() == nil, fmt.Errorf("error reading the ActiveMQ management endpoint response: %s", s.describeRequestError(ctx, endpoint, start, err))
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return false, newAuthError(fmt.Errorf("authentication to the ActiveMQ management endpoint failed with status %d, check the %s", resp.StatusCode, s.getCredentialsDescription()))
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return true, fmt.Errorf("ActiveMQ management endpoint response error code : %d", resp.StatusCode)
+	case resp.StatusCode != http.StatusOK && s.getJolokiaVersion() >= 2 && json.Valid(respBody):
+		// Jolokia 2.x agents may answer a failed read with the HTTP status of the error, the body is
+		// still the Jolokia response telling the error apart, e.g. a missing destination
+	case resp.StatusCode != http.StatusOK:
+		return false, fmt.Errorf("ActiveMQ management endpoint response error code : %d %s", resp.StatusCode, activeMQResponseSnippet(respBody))
+	}
+
+	if err := json.Unmarshal(respBody, response); err != nil {
+		s.logUndecodableResponse(endpoint, respBody, err)
+		return false, fmt.Errorf("error decoding the ActiveMQ management endpoint response %s: %s", activeMQResponseSnippet(respBody), err)
+	}
+
+	return false, nil
+}
+
+// describeRequestError tells a canceled poll and the two kinds of timeouts apart from other request errors:
+// the caller's context deadline and the HTTP client timeout, which only surfaces as a net.Error
+func (s *activeMQScaler) describeRequestError(ctx context.Context, endpoint string, start time.Time, err error) error {
+	switch {
+	case errors.Is(ctx.Err(), context.Canceled):
+		return fmt.Errorf("request to the ActiveMQ management endpoint %s was canceled: %w", endpoint, err)
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return fmt.Errorf("ActiveMQ management endpoint %s timed out after %s, the poll deadline was exceeded: %w", endpoint, time.Since(start).Round(time.Millisecond), err)
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		timeout := s.httpClient.Timeout
+		if timeout <= 0 {
+			timeout = time.Since(start).Round(time.Millisecond)
+		}
+		return fmt.Errorf("ActiveMQ management endpoint %s timed out after %s: %w", endpoint, timeout, err)
+	}
+	return err
+}
+
+// readActiveMQResponseBody reads the response body, decompressing it when it is gzip or deflate encoded. The
+// transport only does so itself when it asked for compression, not when e.g. customHeaders set Accept-Encoding
+// or a gateway compresses unasked.
+func readActiveMQResponseBody(resp *http.Response) ([]byte, error) {
+	var reader io.Reader = resp.Body
+	switch strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))) {
+	case "gzip", "x-gzip":
+		gzipReader, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	case "deflate":
+		zlibReader, err := zlib.NewReader(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer zlibReader.Close()
+		reader = zlibReader
+	}
+	return ioutil.ReadAll(reader)
+}
+
+// activeMQResponseSnippet returns the beginning of a response body to include in error messages
+func activeMQResponseSnippet(body []byte) string {
+	const maxLength = 256
+	snippet := strings.TrimSpace(string(body))
+	if len(snippet) > maxLength {
+		snippet = snippet[:maxLength] + "..."
+	}
+	return fmt.Sprintf("%q", snippet)
+}
+
+// activeMQDebugResponseMaxLength caps the size of the response bodies logged with debugResponse
+const activeMQDebugResponseMaxLength = 4096
+
+// activeMQSecretFieldRegex matches the JSON string fields that may hold credentials, such as password or accessToken
+var activeMQSecretFieldRegex = regexp.MustCompile(`(?i)("[^"]*(?:password|passwd|secret|token|credential|authorization)[^"]*"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// logUndecodableResponse logs the body of a response that could not be decoded at V(1) when debugResponse is set,
+// with the credentials of the scaler and the fields named like credentials redacted, truncated to
+// activeMQDebugResponseMaxLength bytes
+func (s *activeMQScaler) logUndecodableResponse(endpoint string, body []byte, err error) {
+	if !s.metadata.debugResponse {
+		return
+	}
+	redacted := activeMQSecretFieldRegex.ReplaceAllString(string(body), `$1"[redacted]"`)
+	secrets := []string{s.metadata.password, s.metadata.bearerToken, s.metadata.clientSecret}
+	if s.metadata.jolokiaProxyTarget != nil {
+		secrets = append(secrets, s.metadata.jolokiaProxyTarget.Password)
+	}
+	for _, secret := range secrets {
+		if secret != "" {
+			redacted = strings.ReplaceAll(redacted, secret, "[redacted]")
+		}
+	}
+	truncated := len(redacted) > activeMQDebugResponseMaxLength
+	if truncated {
+		redacted = redacted[:activeMQDebugResponseMaxLength]
+	}
+	activeMQLog.V(1).Info("Undecodable ActiveMQ management endpoint response", "managementEndpoint", endpoint, "error", err.Error(), "body", redacted, "truncated", truncated)
+}
@@ -0,0 +1,1460 @@
+package scalers
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+
+	"github.com/kedacore/keda/v2/pkg/scalers/authentication"
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+type activeMQMetadata struct {
+	managementEndpoint       string
+	managementEndpoints      []string
+	aggregation              string
+	skipUnreachableEndpoints bool
+	endpointSelection        string
+	sticky                   bool
+	destinationName          string
+	useRegex                 bool
+	destinationPattern       *regexp.Regexp
+	weightedDestinations     []activeMQWeightedDestination
+	destinationType          string
+	brokerName               string
+	brokerInMetricName       bool // fold brokerName into the generated metric name
+	discoverBroker           bool // brokerName is left out of the trigger and searched on the management endpoint
+	brokerType               string
+	brokerAddress            string
+	targetAttribute          string
+	metricExpression         *activeMQExpression
+	messagesPerConsumer      bool
+	pendingBytes             bool
+	brokerUsage              *activeMQBrokerUsage
+	brokerUsageTarget        int
+	dlq                      bool
+	networkConnector         string // name of the network connector whose pending forwards are read, instead of a destination
+	subscriptionName         string
+	subscriptionClientID     string
+	rateWindow               time.Duration
+	sampleCount              int
+	sampleInterval           time.Duration
+	username                 string
+	password                 string
+	authMode                 authentication.Type
+	bearerToken              string
+	awsSigV4                 *authentication.AWSSigV4Config
+	tokenFile                string
+	valueJSONPath            string
+	treatMissingAsZero       bool
+	// debugResponse logs the redacted body of the responses that can't be decoded
+	debugResponse bool
+	// successStatusCodes are the Jolokia status values of a successful read, 200 unless a gateway answers otherwise
+	successStatusCodes        []int
+	loginURL                  string
+	oauthTokenURL             string
+	clientID                  string
+	clientSecret              string
+	scopes                    []string
+	customHeaders             map[string]string
+	proxyURL                  *url.URL
+	retryCount                int
+	retryInterval             time.Duration
+	cacheTTL                  time.Duration
+	pollJitter                time.Duration // upper bound of the random delay before each poll, no delay when 0
+	emptyQueueStabilization   time.Duration
+	activationOperator        string
+	errorBehavior             string
+	timeout                   time.Duration // custom http timeout for a specific trigger
+	http2                     bool
+	minTLSVersion             uint16        // lowest TLS version accepted from the management endpoints, Go's default when 0
+	idleConnTimeout           time.Duration // how long idle connections are kept open, no limit when 0
+	keepAlive                 time.Duration // interval of the TCP keep-alive probes, the dialer default when 0
+	restAPITemplate           string
+	scheme                    string
+	protocol                  string
+	jolokiaPathPrefix         string
+	jolokiaProxyTarget        *activeMQJolokiaTarget
+	jolokiaVersion            int  // major version of the Jolokia agent, 0 when unknown
+	detectJolokiaVersion      bool // probe the Jolokia agent for its version on the first poll
+	targetQueueSize           int
+	activationTargetQueueSize float64
+	maxQueueSizeCap           float64 // 0 when the reported value is not capped
+	metricMultiplier          float64 // factor applied to the reported value, 0 when it is reported as read
+	metricName                string
+	metricType                v2beta2.MetricTargetType
+	// scalingBrackets map the metric value to a replica count, in ascending threshold order
+	scalingBrackets []activeMQScalingBracket
+	scalerIndex     int
+
+	// client certification
+	enableTLS bool
+	cert      string
+	key       string
+	ca        string
+	unsafeSsl bool
+}
+
+// activeMQTLSVersions are the values of minTLSVersion
+var activeMQTLSVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// activeMQMetadataKeys is the canonical set of trigger metadata keys understood by the scaler, every
+// key read by parseActiveMQMetadata must be listed here or it is rejected as unknown
+var activeMQMetadataKeys = map[string]bool{
+	"activationTargetQueueSize": true,
+	"aggregation":               true,
+	"authMode":                  true,
+	"awsSigV4":                  true,
+	"brokerAddress":             true,
+	"brokerInMetricName":        true,
+	"brokerName":                true,
+	"brokerType":                true,
+	"cacheTTL":                  true,
+	"ca":                        true,
+	"cert":                      true,
+	"clientId":                  true,
+	"customHeaders":             true,
+	"destinationName":           true,
+	"destinationType":           true,
+	"debugResponse":             true,
+	"endpointSelection":         true,
+	"errorBehavior":             true,
+	"activationOperator":        true,
+	"dlqName":                   true,
+	"dlqTarget":                 true,
+	"emptyQueueStabilization":   true,
+	"jolokiaPathPrefix":         true,
+	"jolokiaProxyTarget":        true,
+	"jolokiaProxyUsername":      true,
+	"jolokiaVersion":            true,
+	"http2":                     true,
+	"idleConnTimeout":           true,
+	"keepAlive":                 true,
+	"key":                       true,
+	"loginURL":                  true,
+	"managementEndpoint":        true,
+	"maxQueueSizeCap":           true,
+	"memoryUsageTarget":         true,
+	"metricExpression":          true,
+	"metricMultiplier":          true,
+	"messagesPerConsumer":       true,
+	"metricName":                true,
+	"metricType":                true,
+	"networkConnectorName":      true,
+	"networkConnectorTarget":    true,
+	"minTLSVersion":             true,
+	"password":                  true,
+	"pollJitter":                true,
+	"pendingBytes":              true,
+	"passwordValueFrom":         true,
+	"protocol":                  true,
+	"proxyURL":                  true,
+	"rateWindow":                true,
+	"sampleCount":               true,
+	"sampleInterval":            true,
+	"scalingBrackets":           true,
+	"scheme":                    true,
+	"restAPITemplate":           true,
+	"retryCount":                true,
+	"retryInterval":             true,
+	"skipUnreachableEndpoints":  true,
+	"sticky":                    true,
+	"storeUsageTarget":          true,
+	"subscriptionName":          true,
+	"successStatusCodes":        true,
+	"targetAttribute":           true,
+	"targetQueueSize":           true,
+	"tempUsageTarget":           true,
+	"timeout":                   true,
+	"treatMissingAsZero":        true,
+	"tokenFile":                 true,
+	"tls":                       true,
+	"unsafeSsl":                 true,
+	"useRegex":                  true,
+	"username":                  true,
+	"usernameValueFrom":         true,
+	"valueJSONPath":             true,
+}
+
+// activeMQDestinationKeys are the metadata keys selecting the destination and its target, which the
+// broker usage, dead-letter queue and network connector modes replace
+var activeMQDestinationKeys = []string{"restAPITemplate", "destinationName", "destinationType", "targetQueueSize", "targetAttribute", "rateWindow", "sampleCount", "sampleInterval", "useRegex", "metricExpression", "messagesPerConsumer", "pendingBytes"}
+
+// activeMQMode is a mode of the scaler selected by the trigger metadata, which excludes some of the other keys
+type activeMQMode string
+
+const (
+	activeMQMetricExpressionMode    activeMQMode = "metricExpression"
+	activeMQMessagesPerConsumerMode activeMQMode = "messagesPerConsumer"
+	activeMQPendingBytesMode        activeMQMode = "pendingBytes"
+	activeMQFailoverMode            activeMQMode = "failover"
+	activeMQBrokerUsageMode         activeMQMode = "brokerUsage"
+	activeMQDLQMode                 activeMQMode = "dlq"
+	activeMQNetworkConnectorMode    activeMQMode = "networkConnector"
+	activeMQSubscriptionMode        activeMQMode = "subscription"
+	activeMQStompMode               activeMQMode = "stomp"
+)
+
+// activeMQModeKeys lists the keys each mode is incompatible with.
+type activeMQModeKeys struct {
+	// conflict completes the error message of a forbidden key
+	conflict string
+	// forbidden are the keys of activeMQMetadataKeys the mode can't be used with, it allows all the others
+	forbidden []string
+}
+
+// activeMQModes declares the allowed and forbidden keys of every mode in one place, the parser validates
+// the trigger metadata against it with validateActiveMQModeKeys once it has selected a mode
+var activeMQModes = map[activeMQMode]activeMQModeKeys{
+	activeMQMetricExpressionMode: {
+		conflict:  "can not be used together with metricExpression",
+		forbidden: []string{"targetAttribute", "rateWindow", "sampleCount", "sampleInterval"},
+	},
+	activeMQMessagesPerConsumerMode: {
+		conflict:  "can not be used together with messagesPerConsumer",
+		forbidden: []string{"metricExpression", "targetAttribute", "rateWindow", "sampleCount", "sampleInterval", "valueJSONPath"},
+	},
+	activeMQPendingBytesMode: {
+		conflict:  "can not be used together with pendingBytes",
+		forbidden: []string{"metricExpression", "messagesPerConsumer", "targetAttribute", "rateWindow", "sampleCount", "sampleInterval", "valueJSONPath"},
+	},
+	activeMQFailoverMode: {
+		conflict:  fmt.Sprintf("can not be used with the %s endpointSelection", activeMQFailoverEndpointSelection),
+		forbidden: []string{"aggregation", "skipUnreachableEndpoints"},
+	},
+	activeMQBrokerUsageMode: {
+		conflict:  "can not be used together with a broker usage target",
+		forbidden: activeMQDestinationKeys,
+	},
+	activeMQDLQMode: {
+		conflict:  "can not be used together with dlqName or dlqTarget",
+		forbidden: activeMQDestinationKeys,
+	},
+	activeMQNetworkConnectorMode: {
+		conflict:  "can not be used together with networkConnectorName",
+		forbidden: append([]string{"subscriptionName", "valueJSONPath", "treatMissingAsZero"}, activeMQDestinationKeys...),
+	},
+	activeMQSubscriptionMode: {
+		conflict:  "can not be used together with subscriptionName",
+		forbidden: []string{"targetAttribute", "rateWindow", "sampleCount", "sampleInterval", "metricExpression", "messagesPerConsumer", "pendingBytes"},
+	},
+	activeMQStompMode: {
+		conflict:  fmt.Sprintf("is not supported with the %s protocol", activeMQStompProtocol),
+		forbidden: []string{"restAPITemplate", "jolokiaPathPrefix", "jolokiaProxyTarget", "customHeaders", "proxyURL", "metricExpression", "messagesPerConsumer", "pendingBytes", "valueJSONPath", "treatMissingAsZero", "jolokiaVersion", "http2", "idleConnTimeout", "keepAlive", "successStatusCodes", "debugResponse", "scheme", "networkConnectorName"},
+	},
+}
+
+// parseActiveMQConfig parses the metadata and builds the TLS configuration of the management endpoints
+func parseActiveMQConfig(config *ScalerConfig) (*activeMQMetadata, *tls.Config, error) {
+	meta, err := parseActiveMQMetadata(config)
+	if err != nil {
+		return nil, nil, newConfigError(fmt.Errorf("error parsing ActiveMQ metadata: %s", err))
+	}
+
+	tlsConfig, err := authentication.ParseTLSConfig(meta.tlsParams(), true)
+	if err != nil {
+		return nil, nil, newConfigError(err)
+	}
+	if tlsConfig == nil && meta.scheme == activeMQHTTPSScheme {
+		tlsConfig = &tls.Config{}
+	}
+	// renegotiation is left to its default, tls.RenegotiateNever
+	if meta.minTLSVersion != 0 {
+		tlsConfig.MinVersion = meta.minTLSVersion
+	}
+	return meta, tlsConfig, nil
+}
+
+func parseActiveMQMetadata(config *ScalerConfig) (*activeMQMetadata, error) {
+	meta := activeMQMetadata{}
+
+	if err := validateActiveMQMetadataKeys(config.TriggerMetadata); err != nil {
+		return nil, err
+	}
+
+	if err := parseActiveMQBrokerUsage(config.TriggerMetadata, &meta); err != nil {
+		return nil, err
+	}
+	if err := parseActiveMQDLQ(config.TriggerMetadata, &meta); err != nil {
+		return nil, err
+	}
+	if err := parseActiveMQNetworkConnector(config.TriggerMetadata, &meta); err != nil {
+		return nil, err
+	}
+
+	if val, ok := config.TriggerMetadata["restAPITemplate"]; ok && val != "" {
+		if _, ok := config.TriggerMetadata["jolokiaPathPrefix"]; ok {
+			return nil, errors.New("jolokiaPathPrefix can not be used together with restAPITemplate, the prefix is read from the template")
+		}
+		meta.restAPITemplate = config.TriggerMetadata["restAPITemplate"]
+		var err error
+		if meta, err = getRestAPIParameters(meta); err != nil {
+			return nil, fmt.Errorf("can't parse restAPITemplate : %s ", err)
+		}
+	} else {
+		meta.restAPITemplate = defaultActiveMQRestAPITemplate
+		if val := config.TriggerMetadata["jolokiaPathPrefix"]; val != "" {
+			// the prefix is inserted between the endpoint and the Jolokia path, e.g. /activemq for /activemq/api/jolokia
+			meta.jolokiaPathPrefix = "/" + strings.Trim(val, "/")
+		}
+		if config.TriggerMetadata["managementEndpoint"] == "" {
+			return nil, errors.New("no management endpoint given")
+		}
+		meta.managementEndpoint = resolveActiveMQEnv(config.TriggerMetadata["managementEndpoint"], config.ResolvedEnv)
+		var endpointScheme string
+		for _, endpoint := range strings.Split(meta.managementEndpoint, ",") {
+			if endpoint = strings.TrimSpace(endpoint); endpoint != "" {
+				endpoint, scheme, err := normalizeActiveMQEndpoint(endpoint)
+				if err != nil {
+					return nil, err
+				}
+				if scheme != "" && endpointScheme != "" && scheme != endpointScheme {
+					return nil, errors.New("invalid managementEndpoint - all the endpoints must use the same scheme")
+				}
+				if scheme != "" {
+					endpointScheme = scheme
+				}
+				if err := validateActiveMQEndpoint(endpoint); err != nil {
+					return nil, err
+				}
+				meta.managementEndpoints = append(meta.managementEndpoints, endpoint)
+			}
+		}
+		if len(meta.managementEndpoints) == 0 {
+			return nil, errors.New("no management endpoint given")
+		}
+		if endpointScheme == activeMQHTTPSScheme {
+			// the default template starts with http://, an https:// endpoint asks for TLS like the TLS settings do
+			meta.scheme = activeMQHTTPSScheme
+		}
+
+		if config.TriggerMetadata["destinationName"] == "" && meta.brokerUsage == nil && !meta.dlq && meta.networkConnector == "" {
+			return nil, errors.New("no destination name given")
+		}
+		meta.destinationName = resolveActiveMQEnv(config.TriggerMetadata["destinationName"], config.ResolvedEnv)
+
+		// without brokerName, the broker is searched on the management endpoint, see parseActiveMQBrokerDiscovery
+		meta.brokerName = resolveActiveMQEnv(config.TriggerMetadata["brokerName"], config.ResolvedEnv)
+
+		meta.brokerType = defaultActiveMQBrokerType
+		if val, ok := config.TriggerMetadata["brokerType"]; ok && val != "" {
+			if val != activeMQClassicBrokerType && val != activeMQArtemisBrokerType {
+				return nil, fmt.Errorf("invalid brokerType %q - must be either %s or %s", val, activeMQClassicBrokerType, activeMQArtemisBrokerType)
+			}
+			meta.brokerType = val
+		}
+		if meta.brokerUsage != nil && meta.brokerType == activeMQArtemisBrokerType {
+			return nil, errors.New("broker usage targets are only supported for the classic brokerType")
+		}
+		if meta.networkConnector != "" && meta.brokerType == activeMQArtemisBrokerType {
+			return nil, errors.New("networkConnectorName is only supported for the classic brokerType")
+		}
+
+		if meta.dlq {
+			meta.destinationName = config.TriggerMetadata["dlqName"]
+			if meta.destinationName == "" && meta.brokerType == activeMQArtemisBrokerType {
+				meta.destinationName = defaultArtemisDLQName
+			} else if meta.destinationName == "" {
+				meta.destinationName = defaultActiveMQDLQName
+			}
+		}
+
+		meta.brokerAddress = meta.destinationName
+		if val, ok := config.TriggerMetadata["brokerAddress"]; ok && val != "" {
+			if meta.brokerType != activeMQArtemisBrokerType {
+				return nil, errors.New("brokerAddress is only supported for the artemis brokerType")
+			}
+			meta.brokerAddress = val
+		}
+
+		meta.destinationType = defaultActiveMQDestinationType
+		if val, ok := config.TriggerMetadata["destinationType"]; ok && val != "" {
+			if err := validateActiveMQDestinationType(val); err != nil {
+				return nil, err
+			}
+			meta.destinationType = val
+		}
+	}
+
+	targetQueueSizeKey := "targetQueueSize"
+	if meta.dlq {
+		targetQueueSizeKey = "dlqTarget"
+	} else if meta.networkConnector != "" {
+		targetQueueSizeKey = "networkConnectorTarget"
+	}
+	if val, ok := config.TriggerMetadata[targetQueueSizeKey]; ok {
+		queueSize, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s - must be an integer", targetQueueSizeKey)
+		}
+
+		meta.targetQueueSize = queueSize
+	} else {
+		meta.targetQueueSize = defaultTargetQueueSize
+	}
+
+	activationTargetQueueSize, err := GetActivationThreshold(config, "activationTargetQueueSize", defaultActivationTargetQueueSize)
+	if err != nil {
+		return nil, err
+	}
+	meta.activationTargetQueueSize = activationTargetQueueSize
+
+	if val, ok := config.TriggerMetadata["maxQueueSizeCap"]; ok {
+		maxQueueSizeCap, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+		if err != nil || maxQueueSizeCap <= 0 || math.IsInf(maxQueueSizeCap, 0) {
+			return nil, fmt.Errorf("invalid maxQueueSizeCap - must be a positive number")
+		}
+		meta.maxQueueSizeCap = maxQueueSizeCap
+	}
+
+	if val, ok := config.TriggerMetadata["metricMultiplier"]; ok {
+		metricMultiplier, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+		if err != nil || metricMultiplier <= 0 || math.IsInf(metricMultiplier, 0) {
+			return nil, fmt.Errorf("invalid metricMultiplier %q - must be a positive number", val)
+		}
+		meta.metricMultiplier = metricMultiplier
+	}
+
+	if val, ok := config.AuthParams["username"]; ok && val != "" {
+		meta.username = val
+	} else if val, ok := config.TriggerMetadata["username"]; ok && val != "" {
+		meta.username = resolveActiveMQEnv(val, config.ResolvedEnv)
+	}
+
+	if val, ok := config.AuthParams["password"]; ok && val != "" {
+		meta.password = val
+	} else if val, ok := config.TriggerMetadata["password"]; ok && val != "" {
+		meta.password = resolveActiveMQEnv(val, config.ResolvedEnv)
+	}
+
+	// secret stores may expose the credentials under aliased keys, usernameValueFrom and passwordValueFrom
+	// name the auth param holding the value
+	if val, err := resolveActiveMQValueFrom(config, "username"); err != nil {
+		return nil, err
+	} else if val != "" {
+		meta.username = val
+	}
+	if val, err := resolveActiveMQValueFrom(config, "password"); err != nil {
+		return nil, err
+	} else if val != "" {
+		meta.password = val
+	}
+
+	// secret stores may hold both as a single user:pass value, which only fills in the fields not set on their own
+	if val, ok := config.AuthParams["credentials"]; ok && val != "" {
+		username, password, err := parseActiveMQCredentials(val)
+		if err != nil {
+			return nil, err
+		}
+		if meta.username == "" {
+			meta.username = username
+		}
+		if meta.password == "" {
+			meta.password = password
+		}
+	}
+
+	meta.authMode = authentication.BasicAuthType
+	if val, ok := config.TriggerMetadata["authMode"]; ok && val != "" {
+		meta.authMode = authentication.Type(strings.TrimSpace(val))
+	}
+	if val, ok := config.TriggerMetadata["awsSigV4"]; ok && val != "" {
+		awsSigV4, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid awsSigV4 %q - must be true or false", val)
+		}
+		if awsSigV4 {
+			if config.TriggerMetadata["authMode"] != "" {
+				return nil, errors.New("awsSigV4 can not be used together with authMode")
+			}
+			meta.authMode = activeMQAWSSigV4AuthMode
+		}
+	}
+
+	switch meta.authMode {
+	case authentication.BasicAuthType, activeMQDigestAuthMode:
+		if config.AuthParams["bearerToken"] != "" {
+			return nil, fmt.Errorf("bearer and %s authentication can not be set both", meta.authMode)
+		}
+		if meta.username == "" {
+			return nil, fmt.Errorf("username cannot be empty")
+		}
+		if meta.password == "" {
+			return nil, fmt.Errorf("password cannot be empty")
+		}
+	case authentication.BearerAuthType:
+		if meta.username != "" || meta.password != "" {
+			return nil, errors.New("bearer and basic authentication can not be set both")
+		}
+		// a mounted token file is read on every poll so that rotated tokens are picked up
+		meta.tokenFile = config.TriggerMetadata["tokenFile"]
+		if config.AuthParams["bearerToken"] != "" && meta.tokenFile != "" {
+			return nil, errors.New("bearerToken and tokenFile can not be set both")
+		}
+		if config.AuthParams["bearerToken"] == "" && meta.tokenFile == "" {
+			return nil, errors.New("no bearer token provided")
+		}
+		meta.bearerToken = config.AuthParams["bearerToken"]
+	case activeMQOAuthAuthMode:
+		if meta.username != "" || meta.password != "" || config.AuthParams["bearerToken"] != "" {
+			return nil, errors.New("oauth can not be set together with basic or bearer authentication")
+		}
+		for _, param := range []string{"oauthTokenURL", "clientID", "clientSecret"} {
+			if config.AuthParams[param] == "" {
+				return nil, fmt.Errorf("no %s given", param)
+			}
+		}
+		meta.oauthTokenURL = config.AuthParams["oauthTokenURL"]
+		meta.clientID = config.AuthParams["clientID"]
+		meta.clientSecret = config.AuthParams["clientSecret"]
+		if val := config.AuthParams["scopes"]; val != "" {
+			for _, scope := range strings.Split(val, ",") {
+				if scope = strings.TrimSpace(scope); scope != "" {
+					meta.scopes = append(meta.scopes, scope)
+				}
+			}
+		}
+	case activeMQAWSSigV4AuthMode:
+		// only enabled with awsSigV4, not as an authMode
+		if config.TriggerMetadata["authMode"] != "" {
+			return nil, fmt.Errorf("err incorrect value for authMode is given: %s", meta.authMode)
+		}
+		if meta.username != "" || meta.password != "" || config.AuthParams["bearerToken"] != "" {
+			return nil, errors.New("awsSigV4 can not be set together with basic or bearer authentication")
+		}
+		awsSigV4, err := authentication.ParseAWSSigV4Config(config.AuthParams)
+		if err != nil {
+			return nil, err
+		}
+		meta.awsSigV4 = awsSigV4
+	case activeMQSessionAuthMode:
+		if config.AuthParams["bearerToken"] != "" {
+			return nil, errors.New("session and bearer authentication can not be set both")
+		}
+		if meta.username == "" {
+			return nil, fmt.Errorf("username cannot be empty")
+		}
+		if meta.password == "" {
+			return nil, fmt.Errorf("password cannot be empty")
+		}
+		loginURL, err := url.Parse(config.TriggerMetadata["loginURL"])
+		if err != nil || (loginURL.Scheme != "http" && loginURL.Scheme != "https") || loginURL.Host == "" {
+			return nil, errors.New("invalid loginURL - must be an http or https URL")
+		}
+		meta.loginURL = loginURL.String()
+	default:
+		return nil, fmt.Errorf("err incorrect value for authMode is given: %s", meta.authMode)
+	}
+	if meta.authMode != activeMQSessionAuthMode && config.TriggerMetadata["loginURL"] != "" {
+		return nil, fmt.Errorf("loginURL can only be used with the %s authMode", activeMQSessionAuthMode)
+	}
+	if meta.authMode != authentication.BearerAuthType && config.TriggerMetadata["tokenFile"] != "" {
+		return nil, fmt.Errorf("tokenFile can only be used with the %s authMode", authentication.BearerAuthType)
+	}
+
+	if val, ok := config.TriggerMetadata["customHeaders"]; ok && val != "" {
+		customHeaders, err := parseActiveMQCustomHeaders(val, config.ResolvedEnv)
+		if err != nil {
+			return nil, err
+		}
+		meta.customHeaders = customHeaders
+	}
+
+	meta.aggregation = defaultActiveMQAggregation
+	if val, ok := config.TriggerMetadata["proxyURL"]; ok && val != "" {
+		proxyURL, err := url.Parse(val)
+		if err != nil || proxyURL.Host == "" {
+			return nil, errors.New("invalid proxyURL - must be an absolute URL such as http://proxy:3128")
+		}
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return nil, fmt.Errorf("invalid proxyURL scheme %q - must be one of http, https, socks5", proxyURL.Scheme)
+		}
+		meta.proxyURL = proxyURL
+	}
+
+	if val, ok := config.TriggerMetadata["aggregation"]; ok && val != "" {
+		switch val {
+		case activeMQSumAggregation, activeMQMaxAggregation, activeMQAvgAggregation:
+			meta.aggregation = val
+		default:
+			return nil, fmt.Errorf("invalid aggregation %q - must be one of %s, %s or %s", val, activeMQSumAggregation, activeMQMaxAggregation, activeMQAvgAggregation)
+		}
+	}
+
+	if val, ok := config.TriggerMetadata["skipUnreachableEndpoints"]; ok {
+		skipUnreachableEndpoints, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing skipUnreachableEndpoints: %s", err)
+		}
+		meta.skipUnreachableEndpoints = skipUnreachableEndpoints
+	}
+
+	meta.endpointSelection = defaultActiveMQEndpointSelection
+	if val, ok := config.TriggerMetadata["endpointSelection"]; ok && val != "" {
+		if val != activeMQAllEndpointSelection && val != activeMQFailoverEndpointSelection {
+			return nil, fmt.Errorf("invalid endpointSelection %q - must be either %s or %s", val, activeMQAllEndpointSelection, activeMQFailoverEndpointSelection)
+		}
+		meta.endpointSelection = val
+	}
+	if meta.endpointSelection == activeMQFailoverEndpointSelection {
+		if err := validateActiveMQModeKeys(config.TriggerMetadata, activeMQFailoverMode); err != nil {
+			return nil, err
+		}
+	}
+	if val, ok := config.TriggerMetadata["sticky"]; ok {
+		sticky, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing sticky: %s", err)
+		}
+		if sticky && meta.endpointSelection != activeMQFailoverEndpointSelection {
+			return nil, fmt.Errorf("sticky is only supported with the %s endpointSelection", activeMQFailoverEndpointSelection)
+		}
+		meta.sticky = sticky
+	}
+
+	meta.retryCount = defaultActiveMQRetryCount
+	if val, ok := config.TriggerMetadata["retryCount"]; ok {
+		retryCount, err := strconv.Atoi(val)
+		if err != nil || retryCount < 0 {
+			return nil, fmt.Errorf("invalid retryCount - must be a non-negative integer")
+		}
+		meta.retryCount = retryCount
+	}
+
+	meta.retryInterval = defaultActiveMQRetryInterval
+	if val, ok := config.TriggerMetadata["retryInterval"]; ok {
+		retryInterval, err := strconv.Atoi(val)
+		if err != nil || retryInterval <= 0 {
+			return nil, fmt.Errorf("invalid retryInterval - must be a positive number of milliseconds")
+		}
+		meta.retryInterval = time.Duration(retryInterval) * time.Millisecond
+	}
+
+	if val, ok := config.TriggerMetadata["cacheTTL"]; ok {
+		cacheTTL, err := strconv.Atoi(val)
+		if err != nil || cacheTTL < 0 {
+			return nil, fmt.Errorf("invalid cacheTTL - must be a non-negative number of seconds")
+		}
+		meta.cacheTTL = time.Duration(cacheTTL) * time.Second
+	}
+
+	if val, ok := config.TriggerMetadata["pollJitter"]; ok {
+		pollJitter, err := strconv.Atoi(val)
+		if err != nil || pollJitter < 0 {
+			return nil, fmt.Errorf("invalid pollJitter %q - must be a non-negative number of milliseconds", val)
+		}
+		meta.pollJitter = time.Duration(pollJitter) * time.Millisecond
+	}
+
+	if val, ok := config.TriggerMetadata["emptyQueueStabilization"]; ok {
+		stabilization, err := strconv.Atoi(val)
+		if err != nil || stabilization < 0 {
+			return nil, fmt.Errorf("invalid emptyQueueStabilization - must be a non-negative number of seconds")
+		}
+		meta.emptyQueueStabilization = time.Duration(stabilization) * time.Second
+	}
+
+	meta.activationOperator = defaultActiveMQActivationOperator
+	if val, ok := config.TriggerMetadata["activationOperator"]; ok && val != "" {
+		switch val {
+		case activeMQGtActivationOperator, activeMQGteActivationOperator, activeMQLtActivationOperator, activeMQLteActivationOperator:
+			meta.activationOperator = val
+		default:
+			return nil, fmt.Errorf("invalid activationOperator %q - must be one of %s, %s, %s or %s", val, activeMQGtActivationOperator, activeMQGteActivationOperator, activeMQLtActivationOperator, activeMQLteActivationOperator)
+		}
+	}
+
+	meta.errorBehavior = defaultActiveMQErrorBehavior
+	if val, ok := config.TriggerMetadata["errorBehavior"]; ok && val != "" {
+		switch val {
+		case activeMQErrorErrorBehavior, activeMQLastKnownErrorBehavior, activeMQZeroErrorBehavior:
+			meta.errorBehavior = val
+		default:
+			return nil, fmt.Errorf("invalid errorBehavior %q - must be one of %s, %s or %s", val, activeMQErrorErrorBehavior, activeMQLastKnownErrorBehavior, activeMQZeroErrorBehavior)
+		}
+	}
+
+	meta.timeout = config.GlobalHTTPTimeout
+	if val, ok := config.TriggerMetadata["timeout"]; ok {
+		timeoutMS, err := strconv.Atoi(val)
+		if err != nil || timeoutMS <= 0 {
+			return nil, fmt.Errorf("invalid timeout - must be a positive number of milliseconds")
+		}
+		meta.timeout = time.Duration(timeoutMS) * time.Millisecond
+	}
+
+	if val, ok := config.TriggerMetadata["idleConnTimeout"]; ok {
+		idleConnTimeout, err := strconv.Atoi(val)
+		if err != nil || idleConnTimeout <= 0 {
+			return nil, fmt.Errorf("invalid idleConnTimeout - must be a positive number of seconds")
+		}
+		meta.idleConnTimeout = time.Duration(idleConnTimeout) * time.Second
+	}
+
+	if val, ok := config.TriggerMetadata["keepAlive"]; ok {
+		keepAlive, err := strconv.Atoi(val)
+		if err != nil || keepAlive <= 0 {
+			return nil, fmt.Errorf("invalid keepAlive - must be a positive number of seconds")
+		}
+		meta.keepAlive = time.Duration(keepAlive) * time.Second
+	}
+
+	if val, err := GetFromAuthOrMeta(config, "tls"); err == nil {
+		val = strings.TrimSpace(val)
+
+		if val == "enable" {
+			cert, certErr := GetFromAuthOrMeta(config, "cert")
+			key, keyErr := GetFromAuthOrMeta(config, "key")
+			if certErr != nil || keyErr != nil {
+				return nil, errors.New("both cert and key must be provided when tls is enabled")
+			}
+			meta.cert = cert
+			meta.key = key
+			meta.enableTLS = true
+		} else if val != "disable" {
+			return nil, fmt.Errorf("err incorrect value for TLS given: %s", val)
+		}
+	}
+
+	// A custom CA can be trusted with or without client certificates
+	meta.ca, _ = GetFromAuthOrMeta(config, "ca")
+
+	if val, ok := config.TriggerMetadata["unsafeSsl"]; ok {
+		unsafeSsl, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing unsafeSsl: %s", err)
+		}
+		meta.unsafeSsl = unsafeSsl
+	}
+
+	// scheme picks the scheme of the default template, e.g. https trusting the system CAs without any other TLS setting
+	if val, ok := config.TriggerMetadata["scheme"]; ok && val != "" {
+		scheme := strings.ToLower(strings.TrimSpace(val))
+		switch {
+		case scheme != activeMQHTTPScheme && scheme != activeMQHTTPSScheme:
+			return nil, fmt.Errorf("invalid scheme %q - must be http or https", val)
+		case config.TriggerMetadata["restAPITemplate"] != "":
+			return nil, errors.New("scheme can not be used together with restAPITemplate, the scheme is read from the template")
+		case meta.scheme != "" && meta.scheme != scheme:
+			return nil, fmt.Errorf("scheme %s conflicts with the %s:// managementEndpoint", scheme, meta.scheme)
+		case scheme == activeMQHTTPScheme && (meta.enableTLS || meta.ca != "" || meta.unsafeSsl):
+			return nil, errors.New("scheme http can not be used together with tls, ca or unsafeSsl")
+		}
+		meta.scheme = scheme
+	}
+
+	// A custom restAPITemplate or an https:// managementEndpoint carries its own scheme, otherwise any TLS setting implies HTTPS
+	if meta.scheme == "" {
+		meta.scheme = activeMQHTTPScheme
+		if meta.enableTLS || meta.ca != "" || meta.unsafeSsl {
+			meta.scheme = activeMQHTTPSScheme
+		}
+	}
+
+	if val, ok := config.TriggerMetadata["http2"]; ok && val != "" {
+		http2, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid http2 %q - must be true or false", val)
+		}
+		// HTTP/2 is negotiated with ALPN during the TLS handshake, cleartext HTTP/2 isn't supported
+		if http2 && meta.scheme != activeMQHTTPSScheme {
+			return nil, errors.New("http2 requires an https management endpoint")
+		}
+		meta.http2 = http2
+	}
+
+	if val, ok := config.TriggerMetadata["minTLSVersion"]; ok && val != "" {
+		version, ok := activeMQTLSVersions[val]
+		if !ok {
+			return nil, fmt.Errorf("invalid minTLSVersion %q - must be one of 1.0, 1.1, 1.2, 1.3", val)
+		}
+		if meta.scheme != activeMQHTTPSScheme {
+			return nil, errors.New("minTLSVersion requires TLS towards the management endpoint")
+		}
+		meta.minTLSVersion = version
+	}
+
+	meta.targetAttribute = defaultActiveMQTargetAttribute
+	if val, ok := config.TriggerMetadata["targetAttribute"]; ok && val != "" {
+		if _, ok := activeMQAttributes[val]; !ok {
+			supported := make([]string, 0, len(activeMQAttributes))
+			for attribute := range activeMQAttributes {
+				supported = append(supported, attribute)
+			}
+			sort.Strings(supported)
+			return nil, fmt.Errorf("invalid targetAttribute %q - must be one of %s", val, strings.Join(supported, ", "))
+		}
+		meta.targetAttribute = val
+	}
+	if meta.brokerType == activeMQArtemisBrokerType && meta.destinationType == activeMQTopicDestinationType && meta.targetAttribute != activeMQQueueSizeAttribute {
+		return nil, fmt.Errorf("targetAttribute %s is not available on Artemis addresses", meta.targetAttribute)
+	}
+	if meta.brokerType == activeMQArtemisBrokerType && activeMQAttributes[meta.targetAttribute].artemisName == "" {
+		return nil, fmt.Errorf("targetAttribute %s is only available on %s brokers", meta.targetAttribute, activeMQClassicBrokerType)
+	}
+	if activeMQAttributes[meta.targetAttribute].age {
+		// classic brokers do not expose the age of the oldest message on their destination MBeans
+		if meta.brokerType != activeMQArtemisBrokerType {
+			return nil, fmt.Errorf("targetAttribute %s is only available on %s brokers", meta.targetAttribute, activeMQArtemisBrokerType)
+		}
+		// the age of the oldest message is not additive, report the oldest one of all the brokers by default
+		if config.TriggerMetadata["aggregation"] == "" {
+			meta.aggregation = activeMQMaxAggregation
+		}
+	}
+
+	if val, ok := config.TriggerMetadata["rateWindow"]; ok {
+		if !activeMQAttributes[meta.targetAttribute].cumulative {
+			return nil, fmt.Errorf("rateWindow is only supported for the %s and %s target attributes", activeMQEnqueueCountAttribute, activeMQDequeueCountAttribute)
+		}
+		rateWindow, err := strconv.Atoi(val)
+		if err != nil || rateWindow <= 0 {
+			return nil, fmt.Errorf("invalid rateWindow - must be a positive number of seconds")
+		}
+		meta.rateWindow = time.Duration(rateWindow) * time.Second
+	} else {
+		meta.rateWindow = defaultActiveMQRateWindow
+	}
+
+	meta.sampleCount = defaultActiveMQSampleCount
+	if val, ok := config.TriggerMetadata["sampleCount"]; ok {
+		if !activeMQAttributes[meta.targetAttribute].cumulative {
+			return nil, fmt.Errorf("sampleCount is only supported for the %s and %s target attributes", activeMQEnqueueCountAttribute, activeMQDequeueCountAttribute)
+		}
+		sampleCount, err := strconv.Atoi(val)
+		if err != nil || sampleCount <= 0 {
+			return nil, fmt.Errorf("invalid sampleCount - must be a positive integer")
+		}
+		meta.sampleCount = sampleCount
+	}
+	meta.sampleInterval = defaultActiveMQSampleInterval
+	if val, ok := config.TriggerMetadata["sampleInterval"]; ok {
+		if meta.sampleCount <= 1 {
+			return nil, errors.New("sampleInterval requires a sampleCount greater than 1")
+		}
+		sampleInterval, err := strconv.Atoi(val)
+		if err != nil || sampleInterval <= 0 {
+			return nil, fmt.Errorf("invalid sampleInterval - must be a positive number of seconds")
+		}
+		meta.sampleInterval = time.Duration(sampleInterval) * time.Second
+	}
+	if samplingTime := time.Duration(meta.sampleCount-1) * meta.sampleInterval; samplingTime > maxActiveMQSamplingTime {
+		return nil, fmt.Errorf("invalid sampleCount and sampleInterval - the samples of a poll are spread over %s, must be at most %s", samplingTime, maxActiveMQSamplingTime)
+	}
+
+	if val, ok := config.TriggerMetadata["metricExpression"]; ok && val != "" {
+		if err := validateActiveMQModeKeys(config.TriggerMetadata, activeMQMetricExpressionMode); err != nil {
+			return nil, err
+		}
+		if meta.brokerType == activeMQArtemisBrokerType && meta.destinationType == activeMQTopicDestinationType {
+			return nil, errors.New("metricExpression is not available on Artemis addresses")
+		}
+		expression, err := parseActiveMQExpression(val)
+		if err != nil {
+			return nil, err
+		}
+		if meta.brokerType == activeMQArtemisBrokerType {
+			for _, attribute := range expression.attributes {
+				if activeMQAttributes[attribute].artemisName == "" {
+					return nil, fmt.Errorf("the attribute %s of metricExpression is only available on %s brokers", attribute, activeMQClassicBrokerType)
+				}
+			}
+		}
+		meta.metricExpression = expression
+	}
+
+	if val, ok := config.TriggerMetadata["messagesPerConsumer"]; ok && val != "" {
+		messagesPerConsumer, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid messagesPerConsumer %q - must be true or false", val)
+		}
+		if messagesPerConsumer {
+			if err := validateActiveMQModeKeys(config.TriggerMetadata, activeMQMessagesPerConsumerMode); err != nil {
+				return nil, err
+			}
+			if meta.brokerType == activeMQArtemisBrokerType && meta.destinationType == activeMQTopicDestinationType {
+				return nil, errors.New("messagesPerConsumer is not available on Artemis addresses")
+			}
+			// the backlog per consumer is read like a metricExpression, rounded up, dividing by zero consumers
+			// leaves the whole backlog
+			if meta.metricExpression, err = parseActiveMQExpression(activeMQMessagesPerConsumerExpression); err != nil {
+				return nil, err
+			}
+			meta.messagesPerConsumer = true
+		}
+	}
+
+	if val, ok := config.TriggerMetadata["pendingBytes"]; ok && val != "" {
+		pendingBytes, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pendingBytes %q - must be true or false", val)
+		}
+		if pendingBytes {
+			if err := validateActiveMQModeKeys(config.TriggerMetadata, activeMQPendingBytesMode); err != nil {
+				return nil, err
+			}
+			if meta.brokerType == activeMQArtemisBrokerType {
+				return nil, fmt.Errorf("pendingBytes is only available on %s brokers, Artemis doesn't expose the average message size", activeMQClassicBrokerType)
+			}
+			// the queued bytes are estimated like a metricExpression, from the message count and their average size
+			if meta.metricExpression, err = parseActiveMQExpression(activeMQPendingBytesExpression); err != nil {
+				return nil, err
+			}
+			meta.pendingBytes = true
+		}
+	}
+
+	if val, ok := config.TriggerMetadata["valueJSONPath"]; ok {
+		if meta.metricExpression != nil {
+			return nil, errors.New("valueJSONPath can not be used together with metricExpression")
+		}
+		if strings.TrimSpace(val) == "" {
+			return nil, errors.New("invalid valueJSONPath - must be a path to the value in the Jolokia response, such as value.QueueSize")
+		}
+		meta.valueJSONPath = strings.TrimSpace(val)
+	}
+
+	if val, ok := config.TriggerMetadata["debugResponse"]; ok && val != "" {
+		debugResponse, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid debugResponse %q - must be true or false", val)
+		}
+		meta.debugResponse = debugResponse
+	}
+
+	meta.successStatusCodes = []int{http.StatusOK}
+	if val, ok := config.TriggerMetadata["successStatusCodes"]; ok {
+		meta.successStatusCodes = nil
+		for _, code := range strings.Split(val, ",") {
+			status, err := strconv.Atoi(strings.TrimSpace(code))
+			if err != nil {
+				return nil, fmt.Errorf("invalid successStatusCodes %q - must be a comma separated list of integers", val)
+			}
+			meta.successStatusCodes = append(meta.successStatusCodes, status)
+		}
+	}
+
+	if val, ok := config.TriggerMetadata["treatMissingAsZero"]; ok {
+		treatMissingAsZero, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing treatMissingAsZero: %s", err)
+		}
+		if treatMissingAsZero && meta.brokerUsage != nil {
+			return nil, errors.New("treatMissingAsZero can not be used together with a broker usage target")
+		}
+		meta.treatMissingAsZero = treatMissingAsZero
+	}
+
+	if val, ok := config.TriggerMetadata["useRegex"]; ok {
+		useRegex, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("useRegex has invalid value")
+		}
+		meta.useRegex = useRegex
+	}
+	if meta.useRegex || isActiveMQWildcard(meta.destinationName) {
+		if meta.brokerType == activeMQArtemisBrokerType {
+			return nil, errors.New("destinationName patterns are only supported for the classic brokerType")
+		}
+		expr := meta.destinationName
+		if !meta.useRegex {
+			expr = activeMQWildcardToRegexp(expr)
+		}
+		pattern, err := regexp.Compile(fmt.Sprintf("^(?:%s)$", expr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid destinationName pattern: %s", err)
+		}
+		meta.destinationPattern = pattern
+	}
+	if meta.destinationPattern != nil && meta.metricExpression != nil {
+		return nil, errors.New("metricExpression can not be used with a destinationName pattern")
+	}
+	// Artemis fully qualified queue names contain ::, only a list makes an Artemis destinationName weighted
+	weighted := strings.Contains(config.TriggerMetadata["destinationName"], ",") ||
+		(meta.brokerType == activeMQClassicBrokerType && strings.Contains(config.TriggerMetadata["destinationName"], ":"))
+	if meta.destinationPattern == nil && weighted {
+		if err := parseActiveMQWeightedDestinations(&meta); err != nil {
+			return nil, err
+		}
+	}
+
+	meta.metricType = v2beta2.AverageValueMetricType
+	if val, ok := config.TriggerMetadata["metricType"]; ok && val != "" {
+		metricType := v2beta2.MetricTargetType(val)
+		if metricType != v2beta2.AverageValueMetricType && metricType != v2beta2.ValueMetricType {
+			return nil, fmt.Errorf("invalid metricType %q - must be either %s or %s", val, v2beta2.AverageValueMetricType, v2beta2.ValueMetricType)
+		}
+		meta.metricType = metricType
+	}
+
+	if val, ok := config.TriggerMetadata["scalingBrackets"]; ok && val != "" {
+		// the bracket replicas are reached by reporting replicas times the target, which only an AverageValue target divides
+		if meta.metricType != v2beta2.AverageValueMetricType {
+			return nil, fmt.Errorf("scalingBrackets requires the %s metricType", v2beta2.AverageValueMetricType)
+		}
+		if meta.maxQueueSizeCap > 0 {
+			return nil, errors.New("scalingBrackets can not be used together with maxQueueSizeCap")
+		}
+		if meta.metricMultiplier > 0 {
+			return nil, errors.New("scalingBrackets can not be used together with metricMultiplier")
+		}
+		brackets, err := parseActiveMQScalingBrackets(val)
+		if err != nil {
+			return nil, err
+		}
+		meta.scalingBrackets = brackets
+	}
+
+	if err := parseActiveMQJolokiaProxy(config, &meta); err != nil {
+		return nil, err
+	}
+	if err := parseActiveMQJolokiaVersion(config.TriggerMetadata, &meta); err != nil {
+		return nil, err
+	}
+	if err := parseActiveMQProtocol(config.TriggerMetadata, &meta); err != nil {
+		return nil, err
+	}
+	if err := parseActiveMQSubscription(config.TriggerMetadata, &meta); err != nil {
+		return nil, err
+	}
+	if err := parseActiveMQBrokerDiscovery(&meta); err != nil {
+		return nil, err
+	}
+	if val, ok := config.TriggerMetadata["brokerInMetricName"]; ok && val != "" {
+		brokerInMetricName, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid brokerInMetricName %q - must be true or false", val)
+		}
+		switch {
+		case brokerInMetricName && meta.brokerName == "":
+			return nil, errors.New("brokerInMetricName requires brokerName")
+		case brokerInMetricName && meta.endpointSelection == activeMQFailoverEndpointSelection:
+			// the endpoints of a failover trigger are a single logical broker
+			return nil, fmt.Errorf("brokerInMetricName can not be used with the %s endpointSelection", activeMQFailoverEndpointSelection)
+		}
+		meta.brokerInMetricName = brokerInMetricName
+	}
+
+	destinationName := meta.destinationName
+	if meta.brokerUsage != nil && meta.brokerName == "" {
+		destinationName = meta.brokerUsage.metricSuffix
+	} else if meta.brokerUsage != nil {
+		destinationName = fmt.Sprintf("%s-%s", meta.brokerName, meta.brokerUsage.metricSuffix)
+	} else if meta.dlq {
+		destinationName = fmt.Sprintf("dlq-%s", destinationName)
+	} else if meta.networkConnector != "" {
+		destinationName = fmt.Sprintf("network-%s", activeMQMetricNameReplacer.ReplaceAllString(meta.networkConnector, "-"))
+	} else if meta.subscriptionName != "" {
+		destinationName = fmt.Sprintf("%s-%s-%s", destinationName, meta.subscriptionClientID, meta.subscriptionName)
+	} else if meta.destinationPattern != nil || meta.weightedDestinations != nil {
+		// patterns and weighted lists may contain characters that are not allowed in a metric name
+		destinationName = activeMQMetricNameReplacer.ReplaceAllString(destinationName, "-")
+	}
+	if meta.brokerInMetricName && meta.brokerUsage == nil {
+		// the destination name alone is ambiguous for triggers reading the same queue on different brokers
+		destinationName = fmt.Sprintf("%s-%s", activeMQMetricNameReplacer.ReplaceAllString(meta.brokerName, "-"), destinationName)
+	}
+	metricName := fmt.Sprintf("activemq-%s", destinationName)
+	if suffix := activeMQAttributes[meta.targetAttribute].metricSuffix; suffix != "" {
+		metricName = fmt.Sprintf("%s-%s", metricName, suffix)
+	}
+	if meta.messagesPerConsumer {
+		metricName = fmt.Sprintf("%s-per-consumer", metricName)
+	} else if meta.pendingBytes {
+		metricName = fmt.Sprintf("%s-pending-bytes", metricName)
+	} else if meta.metricExpression != nil {
+		metricName = fmt.Sprintf("%s-%s", metricName, strings.Trim(activeMQMetricNameReplacer.ReplaceAllString(meta.metricExpression.text, "-"), "-"))
+	}
+	if val, ok := config.TriggerMetadata["metricName"]; ok && val != "" {
+		metricName = val
+	}
+	meta.metricName = GenerateMetricNameWithIndex(config.ScalerIndex, kedautil.NormalizeString(metricName))
+
+	meta.scalerIndex = config.ScalerIndex
+
+	return &meta, nil
+}
+
+// parseActiveMQWeightedDestinations parses a destinationName listing several destinations with optional weights,
+// such as high:3,low:1. A destination without a weight counts once.
+func parseActiveMQWeightedDestinations(meta *activeMQMetadata) error {
+	switch {
+	case meta.brokerType != activeMQClassicBrokerType:
+		return errors.New("weighted destinations are only supported for the classic brokerType")
+	case meta.metricExpression != nil:
+		return errors.New("metricExpression can not be used with weighted destinations")
+	}
+
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(meta.destinationName, ",") {
+		entry = strings.TrimSpace(entry)
+		destination := activeMQWeightedDestination{name: entry, weight: 1}
+		if i := strings.LastIndex(entry, ":"); i >= 0 {
+			destination.name = strings.TrimSpace(entry[:i])
+			weight, err := strconv.ParseFloat(strings.TrimSpace(entry[i+1:]), 64)
+			if err != nil || weight <= 0 || math.IsInf(weight, 0) {
+				return fmt.Errorf("invalid weight in destinationName entry %q - must be a positive number", entry)
+			}
+			destination.weight = weight
+		}
+		if destination.name == "" {
+			return fmt.Errorf("invalid destinationName entry %q - must be in the form name or name:weight", entry)
+		}
+		if isActiveMQWildcard(destination.name) {
+			return fmt.Errorf("invalid destinationName entry %q - weighted destinations can not be patterns", entry)
+		}
+		if seen[destination.name] {
+			return fmt.Errorf("destination %s is listed more than once in destinationName", destination.name)
+		}
+		seen[destination.name] = true
+		meta.weightedDestinations = append(meta.weightedDestinations, destination)
+	}
+	return nil
+}
+
+// parseActiveMQBrokerUsage selects the broker usage metric if one of the activeMQBrokerUsages keys is set,
+// such a broker-level metric excludes all the destination related settings
+func parseActiveMQBrokerUsage(metadata map[string]string, meta *activeMQMetadata) error {
+	for key, usage := range activeMQBrokerUsages {
+		val, ok := metadata[key]
+		if !ok {
+			continue
+		}
+		if meta.brokerUsage != nil {
+			return errors.New("only one broker usage target can be set")
+		}
+		target, err := strconv.Atoi(val)
+		if err != nil || target <= 0 || target > 100 {
+			return fmt.Errorf("invalid %s - must be a percentage between 1 and 100", key)
+		}
+		if err := validateActiveMQModeKeys(metadata, activeMQBrokerUsageMode); err != nil {
+			return err
+		}
+		usage := usage
+		meta.brokerUsage = &usage
+		meta.brokerUsageTarget = target
+	}
+	return nil
+}
+
+// parseActiveMQNetworkConnector selects the network connector mode if networkConnectorName is set. The scaler then
+// reads the messages the bridges of the connector have taken from the local broker but not forwarded yet, which
+// replaces the destination and target settings
+func parseActiveMQNetworkConnector(metadata map[string]string, meta *activeMQMetadata) error {
+	_, hasTarget := metadata["networkConnectorTarget"]
+	name := metadata["networkConnectorName"]
+	if name == "" {
+		if hasTarget {
+			return errors.New("networkConnectorTarget requires networkConnectorName")
+		}
+		return nil
+	}
+	if meta.brokerUsage != nil || meta.dlq {
+		return errors.New("networkConnectorName can not be used together with a broker usage target or dead-letter queue monitoring")
+	}
+	if err := validateActiveMQModeKeys(metadata, activeMQNetworkConnectorMode); err != nil {
+		return err
+	}
+	meta.networkConnector = name
+	return nil
+}
+
+// parseActiveMQDLQ selects the dead-letter queue mode if dlqName or dlqTarget is set. The scaler then reads
+// the depth of the dead-letter queue, which replaces the destination and target settings
+func parseActiveMQDLQ(metadata map[string]string, meta *activeMQMetadata) error {
+	_, hasName := metadata["dlqName"]
+	_, hasTarget := metadata["dlqTarget"]
+	if !hasName && !hasTarget {
+		return nil
+	}
+	if meta.brokerUsage != nil {
+		return errors.New("dead-letter queue monitoring can not be used together with a broker usage target")
+	}
+	if err := validateActiveMQModeKeys(metadata, activeMQDLQMode); err != nil {
+		return err
+	}
+	meta.dlq = true
+	return nil
+}
+
+// parseActiveMQSubscription selects the durable topic subscription mode if subscriptionName or clientId is set.
+// The scaler then reads the number of messages pending for the subscriber identified by both.
+func parseActiveMQSubscription(metadata map[string]string, meta *activeMQMetadata) error {
+	meta.subscriptionName, meta.subscriptionClientID = metadata["subscriptionName"], metadata["clientId"]
+	if meta.subscriptionName == "" && meta.subscriptionClientID == "" {
+		return nil
+	}
+	if meta.subscriptionName == "" || meta.subscriptionClientID == "" {
+		return errors.New("subscriptionName and clientId must be set together")
+	}
+	switch {
+	case meta.restAPITemplate != defaultActiveMQRestAPITemplate:
+		return errors.New("subscriptionName can not be used together with restAPITemplate")
+	case meta.brokerType != activeMQClassicBrokerType:
+		return errors.New("durable subscriptions are only supported for the classic brokerType")
+	case meta.destinationType != activeMQTopicDestinationType:
+		return fmt.Errorf("durable subscriptions require the %s destinationType", activeMQTopicDestinationType)
+	case meta.destinationPattern != nil:
+		return errors.New("subscriptionName can not be used with a destinationName pattern")
+	case meta.weightedDestinations != nil:
+		return errors.New("subscriptionName can not be used with weighted destinations")
+	}
+	return validateActiveMQModeKeys(metadata, activeMQSubscriptionMode)
+}
+
+// parseActiveMQJolokiaProxy reads the remote JMX service a Jolokia agent in proxy mode reads the broker through,
+// with the optional credentials of the JMX service which are distinct from those of the agent itself
+func parseActiveMQJolokiaProxy(config *ScalerConfig, meta *activeMQMetadata) error {
+	val, ok := config.TriggerMetadata["jolokiaProxyTarget"]
+	if !ok || val == "" {
+		if _, ok := config.TriggerMetadata["jolokiaProxyUsername"]; ok {
+			return errors.New("jolokiaProxyUsername can only be used together with jolokiaProxyTarget")
+		}
+		return nil
+	}
+	if _, ok := config.TriggerMetadata["restAPITemplate"]; ok {
+		return errors.New("jolokiaProxyTarget can not be used together with restAPITemplate")
+	}
+
+	meta.jolokiaProxyTarget = &activeMQJolokiaTarget{URL: val}
+	if username, err := GetFromAuthOrMeta(config, "jolokiaProxyUsername"); err == nil {
+		meta.jolokiaProxyTarget.User = username
+	}
+	meta.jolokiaProxyTarget.Password = config.AuthParams["jolokiaProxyPassword"]
+	if meta.jolokiaProxyTarget.Password != "" && meta.jolokiaProxyTarget.User == "" {
+		return errors.New("jolokiaProxyPassword requires jolokiaProxyUsername")
+	}
+	return nil
+}
+
+// parseActiveMQJolokiaVersion reads the major version of the Jolokia agent, either given for environments where
+// the version request is not allowed or auto to probe the agent once. Without it the responses of both versions
+// are decoded as Jolokia 1.x ones.
+func parseActiveMQJolokiaVersion(metadata map[string]string, meta *activeMQMetadata) error {
+	val, ok := metadata["jolokiaVersion"]
+	if !ok || val == "" {
+		return nil
+	}
+	switch val {
+	case activeMQJolokiaVersionAuto:
+		meta.detectJolokiaVersion = true
+	case "1", "2":
+		meta.jolokiaVersion, _ = strconv.Atoi(val)
+	default:
+		return fmt.Errorf("invalid jolokiaVersion %q - must be one of 1, 2 or %s", val, activeMQJolokiaVersionAuto)
+	}
+	return nil
+}
+
+// parseActiveMQBrokerDiscovery checks that a brokerName left out of the trigger can be discovered, by searching the
+// broker MBeans of the single management endpoint of a classic broker
+func parseActiveMQBrokerDiscovery(meta *activeMQMetadata) error {
+	if meta.brokerName != "" {
+		return nil
+	}
+	switch {
+	case meta.brokerType != activeMQClassicBrokerType:
+		return fmt.Errorf("no broker name given, the broker can only be discovered for the %s brokerType", activeMQClassicBrokerType)
+	case meta.protocol == activeMQStompProtocol:
+		return fmt.Errorf("no broker name given, the broker can't be discovered with the %s protocol", activeMQStompProtocol)
+	case len(meta.managementEndpoints) > 1:
+		return errors.New("no broker name given, the broker can only be discovered with a single management endpoint")
+	}
+	meta.discoverBroker = true
+	return nil
+}
+
+// parseActiveMQProtocol selects how the queue depth is read. Jolokia over HTTP supports every mode, STOMP only
+// browsing a classic broker queue, for deployments where the HTTP management API is disabled
+func parseActiveMQProtocol(metadata map[string]string, meta *activeMQMetadata) error {
+	meta.protocol = defaultActiveMQProtocol
+	if val, ok := metadata["protocol"]; ok && val != "" {
+		if val != activeMQHTTPProtocol && val != activeMQStompProtocol {
+			return fmt.Errorf("invalid protocol %q - must be either %s or %s", val, activeMQHTTPProtocol, activeMQStompProtocol)
+		}
+		meta.protocol = val
+	}
+	if meta.protocol != activeMQStompProtocol {
+		return nil
+	}
+
+	if err := validateActiveMQModeKeys(metadata, activeMQStompMode); err != nil {
+		return err
+	}
+	switch {
+	case meta.brokerType != activeMQClassicBrokerType:
+		return fmt.Errorf("the %s protocol is only supported for the classic brokerType", activeMQStompProtocol)
+	case meta.destinationType != activeMQQueueDestinationType, meta.destinationPattern != nil, meta.weightedDestinations != nil, meta.brokerUsage != nil:
+		return fmt.Errorf("the %s protocol only supports a single queue", activeMQStompProtocol)
+	case meta.targetAttribute != activeMQQueueSizeAttribute:
+		return fmt.Errorf("the %s protocol only supports the %s targetAttribute", activeMQStompProtocol, activeMQQueueSizeAttribute)
+	case meta.authMode != authentication.BasicAuthType:
+		return fmt.Errorf("the %s protocol only supports the %s authMode", activeMQStompProtocol, authentication.BasicAuthType)
+	}
+	return nil
+}
+
+// normalizeActiveMQEndpoint strips an http:// or https:// scheme and the trailing slashes from a management endpoint,
+// which is given as host:port, and returns the scheme that was stripped
+func normalizeActiveMQEndpoint(endpoint string) (string, string, error) {
+	var scheme string
+	if i := strings.Index(endpoint, "://"); i >= 0 {
+		scheme = strings.ToLower(endpoint[:i])
+		if scheme != activeMQHTTPScheme && scheme != activeMQHTTPSScheme {
+			return "", "", fmt.Errorf("invalid management endpoint %q - the %s scheme is not supported, must be in the form host:port", endpoint, endpoint[:i])
+		}
+		endpoint = endpoint[i+len("://"):]
+	}
+	endpoint = strings.TrimRight(endpoint, "/")
+	if endpoint == "" {
+		return "", "", errors.New("invalid management endpoint - must be in the form host:port")
+	}
+	return endpoint, scheme, nil
+}
+
+// validateActiveMQEndpoint checks the host and port of a management endpoint, IPv6 literals such as [2001:db8::1]:8161
+// must be bracketed so that their port can be told apart
+func validateActiveMQEndpoint(endpoint string) error {
+	hostPort := endpoint
+	if i := strings.Index(hostPort, "/"); i >= 0 {
+		hostPort = hostPort[:i]
+	}
+	if strings.Count(hostPort, ":") > 1 && !strings.HasPrefix(hostPort, "[") {
+		return fmt.Errorf("invalid management endpoint %q - IPv6 addresses must be enclosed in brackets, e.g. [2001:db8::1]:8161", endpoint)
+	}
+	if u, err := url.Parse("//" + hostPort); err != nil || u.Host != hostPort {
+		return fmt.Errorf("invalid management endpoint %q - must be in the form host:port", endpoint)
+	}
+	return nil
+}
+
+// validateActiveMQMetadataKeys rejects metadata keys the scaler doesn't know, so that typos don't silently fall back to defaults
+func validateActiveMQMetadataKeys(metadata map[string]string) error {
+	var unknown []string
+	for key := range metadata {
+		if !activeMQMetadataKeys[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+	return fmt.Errorf("unknown metadata keys: %s", strings.Join(unknown, ", "))
+}
+
+// validateActiveMQModeKeys rejects the metadata keys the mode forbids in activeMQModes
+func validateActiveMQModeKeys(metadata map[string]string, mode activeMQMode) error {
+	keys := activeMQModes[mode]
+	for _, key := range keys.forbidden {
+		if _, ok := metadata[key]; ok {
+			return fmt.Errorf("%s %s", key, keys.conflict)
+		}
+	}
+	return nil
+}
+
+// resolveActiveMQEnv returns the value of the environment variable the metadata value names, or the value itself
+// when it doesn't name a set environment variable
+func resolveActiveMQEnv(value string, resolvedEnv map[string]string) string {
+	if val, ok := resolvedEnv[value]; ok && val != "" {
+		return val
+	}
+	return value
+}
+
+// parseActiveMQScalingBrackets parses comma separated threshold:replicas pairs such as 1:1,100:3,1000:10, the
+// thresholds must be in ascending order
+func parseActiveMQScalingBrackets(scalingBrackets string) ([]activeMQScalingBracket, error) {
+	var brackets []activeMQScalingBracket
+	for _, entry := range strings.Split(scalingBrackets, ",") {
+		kv := strings.SplitN(entry, ":", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid scalingBrackets entry %q - must be in the form threshold:replicas", entry)
+		}
+		threshold, err := strconv.ParseFloat(strings.TrimSpace(kv[0]), 64)
+		if err != nil || threshold < 0 || math.IsInf(threshold, 0) {
+			return nil, fmt.Errorf("invalid scalingBrackets threshold %q - must be a non-negative number", kv[0])
+		}
+		replicas, err := strconv.Atoi(strings.TrimSpace(kv[1]))
+		if err != nil || replicas < 0 {
+			return nil, fmt.Errorf("invalid scalingBrackets replicas %q - must be a non-negative integer", kv[1])
+		}
+		if len(brackets) > 0 && threshold <= brackets[len(brackets)-1].threshold {
+			return nil, fmt.Errorf("invalid scalingBrackets %q - the thresholds must be in ascending order", scalingBrackets)
+		}
+		brackets = append(brackets, activeMQScalingBracket{threshold: threshold, replicas: replicas})
+	}
+	return brackets, nil
+}
+
+// resolveActiveMQValueFrom returns the value of the auth param named by the <key>ValueFrom metadata, if set.
+// Only one level of indirection is resolved, the value of the referenced auth param is used as is.
+func resolveActiveMQValueFrom(config *ScalerConfig, key string) (string, error) {
+	valueFromKey := key + "ValueFrom"
+	ref := strings.TrimSpace(config.TriggerMetadata[valueFromKey])
+	if ref == "" {
+		return "", nil
+	}
+	if config.AuthParams[key] != "" || config.TriggerMetadata[key] != "" {
+		return "", fmt.Errorf("%s and %s can not be set both", key, valueFromKey)
+	}
+	if ref == key {
+		return "", fmt.Errorf("invalid %s %q - must name another auth param", valueFromKey, ref)
+	}
+	val := config.AuthParams[ref]
+	if val == "" {
+		return "", fmt.Errorf("invalid %s %q - no such auth param given", valueFromKey, ref)
+	}
+	return val, nil
+}
+
+// parseActiveMQCredentials splits user:pass credentials on the first colon, so that the password may contain colons
+func parseActiveMQCredentials(credentials string) (string, string, error) {
+	kv := strings.SplitN(credentials, ":", 2)
+	if len(kv) != 2 || kv[0] == "" {
+		return "", "", errors.New("invalid credentials - must be in the form username:password")
+	}
+	return kv[0], kv[1], nil
+}
+
+// parseActiveMQCustomHeaders parses comma separated key=value pairs, values naming an environment variable are resolved from it
+func parseActiveMQCustomHeaders(customHeaders string, resolvedEnv map[string]string) (map[string]string, error) {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(customHeaders, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			return nil, fmt.Errorf("invalid customHeaders entry %q - must be in the form key=value", pair)
+		}
+
+		headers[strings.TrimSpace(kv[0])] = resolveActiveMQEnv(strings.TrimSpace(kv[1]), resolvedEnv)
+	}
+	return headers, nil
+}
+
+// validateActiveMQDestinationType checks that destinationType is one of the destination types exposed by the Broker MBean
+func validateActiveMQDestinationType(destinationType string) error {
+	switch destinationType {
+	case activeMQQueueDestinationType, activeMQTopicDestinationType:
+		return nil
+	default:
+		return fmt.Errorf("invalid destinationType %q - must be either %s or %s", destinationType, activeMQQueueDestinationType, activeMQTopicDestinationType)
+	}
+}
@@ -0,0 +1,199 @@
+package scalers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// activeMQExpression is a compiled metricExpression, an arithmetic expression over destination attributes
+// such as queueSize/consumerCount. Only numbers, attribute names, + - * / and parentheses are supported.
+type activeMQExpression struct {
+	text       string
+	root       activeMQExpressionNode
+	attributes []string // attributes the expression reads, in order of first use
+}
+
+type activeMQExpressionNode interface {
+	eval(values map[string]float64) float64
+}
+
+type activeMQNumberNode float64
+
+func (n activeMQNumberNode) eval(map[string]float64) float64 { return float64(n) }
+
+type activeMQAttributeNode string
+
+func (n activeMQAttributeNode) eval(values map[string]float64) float64 { return values[string(n)] }
+
+type activeMQNegateNode struct{ operand activeMQExpressionNode }
+
+func (n activeMQNegateNode) eval(values map[string]float64) float64 { return -n.operand.eval(values) }
+
+type activeMQBinaryNode struct {
+	operator    byte
+	left, right activeMQExpressionNode
+}
+
+func (n activeMQBinaryNode) eval(values map[string]float64) float64 {
+	left, right := n.left.eval(values), n.right.eval(values)
+	switch n.operator {
+	case '+':
+		return left + right
+	case '-':
+		return left - right
+	case '*':
+		return left * right
+	default:
+		// e.g. queueSize/consumerCount without consumers is the whole backlog
+		if right == 0 {
+			return left
+		}
+		return left / right
+	}
+}
+
+// eval computes the expression from the values of its attributes
+func (e *activeMQExpression) eval(values map[string]float64) float64 {
+	return e.root.eval(values)
+}
+
+// parseActiveMQExpression compiles a metricExpression, attribute names are matched case-insensitively
+// against the supported non cumulative target attributes
+func parseActiveMQExpression(text string) (*activeMQExpression, error) {
+	parser := &activeMQExpressionParser{text: text, expression: &activeMQExpression{text: text}}
+	root, err := parser.parseSum()
+	if err != nil {
+		return nil, fmt.Errorf("invalid metricExpression %q: %s", text, err)
+	}
+	parser.skipSpaces()
+	if parser.pos < len(text) {
+		return nil, fmt.Errorf("invalid metricExpression %q: unexpected %q at position %d", text, text[parser.pos], parser.pos)
+	}
+	if len(parser.expression.attributes) == 0 {
+		return nil, fmt.Errorf("invalid metricExpression %q: no attribute is read", text)
+	}
+	parser.expression.root = root
+	return parser.expression, nil
+}
+
+type activeMQExpressionParser struct {
+	text       string
+	pos        int
+	expression *activeMQExpression
+}
+
+func (p *activeMQExpressionParser) skipSpaces() {
+	for p.pos < len(p.text) && p.text[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+// peek returns the next character after spaces, or 0 at the end of the expression
+func (p *activeMQExpressionParser) peek() byte {
+	p.skipSpaces()
+	if p.pos >= len(p.text) {
+		return 0
+	}
+	return p.text[p.pos]
+}
+
+func (p *activeMQExpressionParser) parseSum() (activeMQExpressionNode, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for operator := p.peek(); operator == '+' || operator == '-'; operator = p.peek() {
+		p.pos++
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = activeMQBinaryNode{operator: operator, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *activeMQExpressionParser) parseProduct() (activeMQExpressionNode, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	for operator := p.peek(); operator == '*' || operator == '/'; operator = p.peek() {
+		p.pos++
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		left = activeMQBinaryNode{operator: operator, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *activeMQExpressionParser) parseOperand() (activeMQExpressionNode, error) {
+	c := p.peek()
+	switch {
+	case c == 0:
+		return nil, fmt.Errorf("unexpected end")
+	case c == '-':
+		p.pos++
+		operand, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return activeMQNegateNode{operand: operand}, nil
+	case c == '(':
+		p.pos++
+		node, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, fmt.Errorf("missing ) at position %d", p.pos)
+		}
+		p.pos++
+		return node, nil
+	case c == '.' || (c >= '0' && c <= '9'):
+		start := p.pos
+		for p.pos < len(p.text) && (p.text[p.pos] == '.' || (p.text[p.pos] >= '0' && p.text[p.pos] <= '9')) {
+			p.pos++
+		}
+		number, err := strconv.ParseFloat(p.text[start:p.pos], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", p.text[start:p.pos])
+		}
+		return activeMQNumberNode(number), nil
+	case unicode.IsLetter(rune(c)):
+		start := p.pos
+		for p.pos < len(p.text) && (unicode.IsLetter(rune(p.text[p.pos])) || unicode.IsDigit(rune(p.text[p.pos]))) {
+			p.pos++
+		}
+		return p.attribute(p.text[start:p.pos])
+	default:
+		return nil, fmt.Errorf("unexpected %q at position %d", c, p.pos)
+	}
+}
+
+// attribute resolves an attribute name to the target attribute it reads
+func (p *activeMQExpressionParser) attribute(name string) (activeMQExpressionNode, error) {
+	for attribute, definition := range activeMQAttributes {
+		if !strings.EqualFold(attribute, name) {
+			continue
+		}
+		if definition.cumulative {
+			return nil, fmt.Errorf("the cumulative attribute %s can not be used, only current values are supported", attribute)
+		}
+		if definition.age {
+			return nil, fmt.Errorf("the age attribute %s can not be used, only counts are supported", attribute)
+		}
+		for _, known := range p.expression.attributes {
+			if known == attribute {
+				return activeMQAttributeNode(attribute), nil
+			}
+		}
+		p.expression.attributes = append(p.expression.attributes, attribute)
+		return activeMQAttributeNode(attribute), nil
+	}
+	return nil, fmt.Errorf("unknown attribute %s", name)
+}
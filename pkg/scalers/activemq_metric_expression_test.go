@@ -0,0 +1,61 @@
+package scalers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseActiveMQExpression(t *testing.T) {
+	values := map[string]float64{"QueueSize": 12, "ConsumerCount": 4}
+	noConsumers := map[string]float64{"QueueSize": 12, "ConsumerCount": 0}
+
+	testCases := []struct {
+		expression  string
+		attributes  []string
+		value       float64
+		noConsumers float64
+		isError     bool
+	}{
+		{"queueSize/consumerCount", []string{"QueueSize", "ConsumerCount"}, 3, 12, false},
+		{"QueueSize / ConsumerCount", []string{"QueueSize", "ConsumerCount"}, 3, 12, false},
+		{"queueSize - 2 * consumerCount", []string{"QueueSize", "ConsumerCount"}, 4, 12, false},
+		{"(queueSize - 2) * consumerCount", []string{"QueueSize", "ConsumerCount"}, 40, 0, false},
+		{"queueSize / (consumerCount + 1)", []string{"QueueSize", "ConsumerCount"}, 2.4, 12, false},
+		{"-consumerCount + queueSize", []string{"ConsumerCount", "QueueSize"}, 8, 12, false},
+		{"queueSize * 0.5", []string{"QueueSize"}, 6, 6, false},
+		{"queueSize + queueSize", []string{"QueueSize"}, 24, 24, false},
+		{"", nil, 0, 0, true},
+		{"42", nil, 0, 0, true},
+		{"queueSize/", nil, 0, 0, true},
+		{"(queueSize", nil, 0, 0, true},
+		{"queueSize)", nil, 0, 0, true},
+		{"queueSize % 2", nil, 0, 0, true},
+		{"queueSize/1.2.3", nil, 0, 0, true},
+		{"unknownAttribute", nil, 0, 0, true},
+		{"enqueueCount/consumerCount", nil, 0, 0, true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.expression, func(t *testing.T) {
+			expression, err := parseActiveMQExpression(testCase.expression)
+			if testCase.isError {
+				if err == nil {
+					t.Error("Expected error but got success")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("Expected success but got error", err)
+			}
+			if !reflect.DeepEqual(expression.attributes, testCase.attributes) {
+				t.Errorf("Wrong attributes: %v, expected: %v", expression.attributes, testCase.attributes)
+			}
+			if value := expression.eval(values); value != testCase.value {
+				t.Errorf("Wrong value: %g, expected: %g", value, testCase.value)
+			}
+			if value := expression.eval(noConsumers); value != testCase.noConsumers {
+				t.Errorf("Wrong value without consumers: %g, expected: %g", value, testCase.noConsumers)
+			}
+		})
+	}
+}
@@ -1,257 +1,968 @@
 package scalers
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/rand"
 	"net/http"
+	"net/http/cookiejar"
 	"net/url"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
-	"text/template"
+	"sync"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
 	v2beta2 "k8s.io/api/autoscaling/v2beta2"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/metrics/pkg/apis/external_metrics"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 
+	"github.com/kedacore/keda/v2/pkg/scalers/authentication"
 	kedautil "github.com/kedacore/keda/v2/pkg/util"
+	"github.com/kedacore/keda/v2/version"
 )
 
 type activeMQScaler struct {
 	metadata   *activeMQMetadata
 	httpClient *http.Client
+	// tlsConfig is used for STOMP connections, nil unless TLS is configured
+	tlsConfig *tls.Config
+
+	// fetcher reads the samples from the management endpoints, selected by protocol
+	fetcher activeMQMetricFetcher
+
+	// tokenManager is only set when the oauth authMode is used
+	tokenManager *activeMQTokenManager
+	// sessionManager is only set when the session authMode is used
+	sessionManager *activeMQSessionManager
+	// digestTransport is only set when the digest authMode is used, it wraps the transport of httpClient
+	digestTransport *activeMQDigestTransport
+
+	// index of the management endpoint tried first with the failover endpointSelection
+	endpointLock   sync.Mutex
+	activeEndpoint int
+
+	// metric value cached for cacheTTL, concurrent callers wait for the poll in flight instead of polling again
+	cacheLock   sync.Mutex
+	cachedValue float64
+	cachedAt    time.Time
+	cachePoll   *activeMQPoll
+
+	// rate tracking for cumulative counter attributes
+	rateLock     sync.Mutex
+	rateBaseline *activeMQSample
+	lastRate     float64
+	// ring buffer of the last sampleCount rates, averaged when sampleCount is above 1
+	rateSamples    []float64
+	nextRateSample int
+
+	// last time the metric value was above the activation threshold, for emptyQueueStabilization
+	activityLock sync.Mutex
+	activeAt     time.Time
+
+	// last successfully read metric value, for the lastKnown errorBehavior and LastMetricValue
+	lastKnownLock  sync.Mutex
+	lastKnownValue *float64
+
+	// major version of the Jolokia agent detected with jolokiaVersion auto, 0 until the first successful probe
+	versionLock     sync.Mutex
+	detectedVersion int
+
+	// held while the broker left out of the trigger is searched, brokerLock guards the discoveredBrokerName found
+	discoveryLock        sync.Mutex
+	brokerLock           sync.Mutex
+	discoveredBrokerName string
+}
+
+// activeMQJitter returns the random delay before a poll, up to max, replaced in tests
+var activeMQJitter = func(max time.Duration) time.Duration {
+	return time.Duration(rand.Int63n(int64(max)))
+}
+
+// activeMQAttribute describes a destination attribute the scaler can scale on
+type activeMQAttribute struct {
+	artemisName  string // name of the attribute on the Artemis queue MBean, empty if Artemis has no such attribute
+	metricSuffix string // appended to the generated metric name, empty for the default attribute
+	cumulative   bool   // the attribute is an ever-increasing counter, the scaler reports its rate per second
+	age          bool   // the attribute is the age in milliseconds of the oldest message, the scaler reports it in seconds
+}
+
+var activeMQAttributes = map[string]activeMQAttribute{
+	activeMQQueueSizeAttribute:     {artemisName: "MessageCount"},
+	activeMQConsumerCountAttribute: {artemisName: "ConsumerCount", metricSuffix: "consumer-count"},
+	activeMQEnqueueCountAttribute:  {artemisName: "MessagesAdded", metricSuffix: "enqueue-rate", cumulative: true},
+	activeMQDequeueCountAttribute:  {artemisName: "MessagesAcknowledged", metricSuffix: "dequeue-rate", cumulative: true},
+	activeMQMessageAgeAttribute:    {artemisName: "FirstMessageAge", metricSuffix: "age", age: true},
+	// messages delivered to consumers but not acknowledged yet, a growing count points at slow or stuck consumers
+	activeMQInFlightCountAttribute: {artemisName: "DeliveringCount", metricSuffix: "in-flight"},
+	// average size in bytes of the messages of the destination, only exposed by classic brokers
+	activeMQAverageMessageSizeAttribute: {metricSuffix: "average-message-size"},
 }
 
-type activeMQMetadata struct {
-	managementEndpoint string
-	destinationName    string
-	brokerName         string
-	username           string
-	password           string
-	restAPITemplate    string
-	targetQueueSize    int
-	metricName         string
-	scalerIndex        int
+// activeMQScalingBracket is one step of scalingBrackets, metric values from threshold on ask for replicas
+type activeMQScalingBracket struct {
+	threshold float64
+	replicas  int
 }
 
-type activeMQMonitoring struct {
-	MsgCount  int   `json:"value"`
-	Status    int   `json:"status"`
-	Timestamp int64 `json:"timestamp"`
+// activeMQWeightedDestination is one entry of a weighted destinationName list such as high:3,low:1, the
+// scaler reports the sum of the values read for each destination multiplied by its weight
+type activeMQWeightedDestination struct {
+	name   string
+	weight float64
+}
+
+// activeMQBrokerUsage describes a broker-level usage percentage the scaler can scale on instead of a destination attribute
+type activeMQBrokerUsage struct {
+	attribute    string // name of the attribute on the classic Broker MBean
+	metricSuffix string
+}
+
+// activeMQBrokerUsages maps the metadata keys selecting a broker usage metric, and holding its target percentage, to the usage they read
+var activeMQBrokerUsages = map[string]activeMQBrokerUsage{
+	"memoryUsageTarget": {attribute: "MemoryPercentUsage", metricSuffix: "memory-usage"},
+	"storeUsageTarget":  {attribute: "StorePercentUsage", metricSuffix: "store-usage"},
+	"tempUsageTarget":   {attribute: "TempPercentUsage", metricSuffix: "temp-usage"},
+}
+
+// activeMQMetricFetcher reads the value the scaler scales on from one management endpoint, behind it sit the
+// Jolokia over HTTP and the STOMP backends
+type activeMQMetricFetcher interface {
+	// Fetch reads a sample from the endpoint, reporting whether a failure means the endpoint is unreachable
+	Fetch(ctx context.Context, endpoint string) (activeMQSample, bool, error)
+}
+
+// newActiveMQMetricFetcher returns the fetcher of the configured protocol
+func newActiveMQMetricFetcher(s *activeMQScaler) activeMQMetricFetcher {
+	if s.metadata.protocol == activeMQStompProtocol {
+		return &activeMQStompFetcher{scaler: s}
+	}
+	return &activeMQJolokiaFetcher{scaler: s}
+}
+
+// activeMQSample is a value read from the management endpoints along with the Jolokia timestamp of the read
+type activeMQSample struct {
+	value     float64
+	timestamp int64
 }
 
 const (
-	defaultTargetQueueSize         = 10
-	defaultActiveMQRestAPITemplate = "http://{{.ManagementEndpoint}}/api/jolokia/read/org.apache.activemq:type=Broker,brokerName={{.BrokerName}},destinationType=Queue,destinationName={{.DestinationName}}/QueueSize"
+	defaultTargetQueueSize           = 10
+	defaultActivationTargetQueueSize = 0
+	defaultActiveMQRestAPITemplate   = "{{.Scheme}}://{{.ManagementEndpoint}}{{.PathPrefix}}/api/jolokia/read/org.apache.activemq:type=Broker,brokerName={{.BrokerName}},destinationType={{.DestinationType}},destinationName={{.DestinationName}}/{{.Attribute}}"
+
+	// durable topic subscribers are registered under their topic with the client ID and subscription name
+	defaultActiveMQSubscriptionRestAPITemplate = "{{.Scheme}}://{{.ManagementEndpoint}}{{.PathPrefix}}/api/jolokia/read/org.apache.activemq:type=Broker,brokerName={{.BrokerName}},destinationType=Topic,destinationName={{.DestinationName}},endpoint=Consumer,clientId={{.ClientID}},consumerId=Durable({{.ClientID}}_{{.SubscriptionName}})/{{.Attribute}}"
+	defaultActiveMQBrokerRestAPITemplate       = "{{.Scheme}}://{{.ManagementEndpoint}}{{.PathPrefix}}/api/jolokia/read/org.apache.activemq:type=Broker,brokerName={{.BrokerName}}/{{.Attribute}}"
+	defaultActiveMQDestinationsRestAPITemplate = "{{.Scheme}}://{{.ManagementEndpoint}}{{.PathPrefix}}/api/jolokia/read/org.apache.activemq:type=Broker,brokerName={{.BrokerName}}/{{.DestinationType}}s"
+	defaultActiveMQBulkRestAPITemplate         = "{{.Scheme}}://{{.ManagementEndpoint}}{{.PathPrefix}}/api/jolokia/"
+	defaultArtemisBulkRestAPITemplate          = "{{.Scheme}}://{{.ManagementEndpoint}}{{.PathPrefix}}/console/jolokia/"
+	// a standalone Jolokia agent in proxy mode is deployed on its own, not under a broker web console
+	defaultJolokiaProxyRestAPITemplate = "{{.Scheme}}://{{.ManagementEndpoint}}{{.PathPrefix}}/jolokia/"
+
+	// Artemis exposes queues under their address, topics are read from the (multicast) address itself
+	defaultArtemisQueueRestAPITemplate = "{{.Scheme}}://{{.ManagementEndpoint}}{{.PathPrefix}}/console/jolokia/read/org.apache.activemq.artemis:broker=\"{{.BrokerName}}\",component=addresses,address=\"{{.BrokerAddress}}\",subcomponent=queues,routing-type=\"anycast\",queue=\"{{.DestinationName}}\"/{{.Attribute}}"
+	defaultArtemisTopicRestAPITemplate = "{{.Scheme}}://{{.ManagementEndpoint}}{{.PathPrefix}}/console/jolokia/read/org.apache.activemq.artemis:broker=\"{{.BrokerName}}\",component=addresses,address=\"{{.BrokerAddress}}\"/MessageCount"
+
+	activeMQClassicBrokerType   = "classic"
+	activeMQArtemisBrokerType   = "artemis"
+	defaultActiveMQBrokerType   = activeMQClassicBrokerType
+	activeMQArtemisMBeanDomain  = "org.apache.activemq.artemis"
+	activeMQArtemisCorsTemplate = "%s://%s"
+
+	// jolokiaVersion values, auto probes the agent for its version
+	activeMQJolokiaVersionAuto = "auto"
+
+	// Jolokia search pattern of the classic broker MBeans, the destinations have more key properties and don't match
+	activeMQBrokerSearchPattern = "org.apache.activemq:type=Broker,brokerName=*"
+
+	// error type of the Jolokia reads of an MBean that is not registered
+	activeMQInstanceNotFoundErrorType = "javax.management.InstanceNotFoundException"
+
+	// paths Jolokia is served under by the classic and Artemis web consoles
+	activeMQJolokiaPath = "/api/jolokia/"
+	artemisJolokiaPath  = "/console/jolokia/"
+
+	activeMQQueueDestinationType   = "Queue"
+	activeMQTopicDestinationType   = "Topic"
+	defaultActiveMQDestinationType = activeMQQueueDestinationType
+
+	// default dead-letter queues of the classic and Artemis brokers
+	defaultActiveMQDLQName = "ActiveMQ.DLQ"
+	defaultArtemisDLQName  = "DLQ"
+
+	activeMQQueueSizeAttribute          = "QueueSize"
+	activeMQConsumerCountAttribute      = "ConsumerCount"
+	activeMQEnqueueCountAttribute       = "EnqueueCount"
+	activeMQDequeueCountAttribute       = "DequeueCount"
+	activeMQMessageAgeAttribute         = "MessageAge"
+	activeMQInFlightCountAttribute      = "InFlightCount"
+	activeMQAverageMessageSizeAttribute = "AverageMessageSize"
+	defaultActiveMQTargetAttribute      = activeMQQueueSizeAttribute
+	defaultActiveMQRateWindow           = 60 * time.Second
+	defaultActiveMQSampleCount          = 1
+	defaultActiveMQSampleInterval       = time.Second
+	// maxActiveMQSamplingTime bounds the time the samples of a poll are spread over, (sampleCount-1)*sampleInterval,
+	// to keep a poll well within the deadline of the external metrics requests
+	maxActiveMQSamplingTime = 30 * time.Second
+
+	// activeMQMessagesPerConsumerExpression is the metricExpression read by the messagesPerConsumer mode
+	activeMQMessagesPerConsumerExpression = "QueueSize/ConsumerCount"
+	// activeMQPendingBytesExpression is the metricExpression read by the pendingBytes mode, an estimate of the bytes queued
+	activeMQPendingBytesExpression = "QueueSize*AverageMessageSize"
+
+	activeMQSumAggregation     = "sum"
+	activeMQMaxAggregation     = "max"
+	activeMQAvgAggregation     = "avg"
+	defaultActiveMQAggregation = activeMQSumAggregation
+
+	// how the metric value is compared to activationTargetQueueSize to tell whether the scaler is active
+	activeMQGtActivationOperator      = "gt"
+	activeMQGteActivationOperator     = "gte"
+	activeMQLtActivationOperator      = "lt"
+	activeMQLteActivationOperator     = "lte"
+	defaultActiveMQActivationOperator = activeMQGtActivationOperator
+
+	// what the scaler reports when the management endpoints are unreachable: the error, the last value read or 0
+	activeMQErrorErrorBehavior     = "error"
+	activeMQLastKnownErrorBehavior = "lastKnown"
+	activeMQZeroErrorBehavior      = "zero"
+	defaultActiveMQErrorBehavior   = activeMQErrorErrorBehavior
+
+	// endpoint selections, either all endpoints are read and aggregated or the first one answering is used
+	activeMQAllEndpointSelection      = "all"
+	activeMQFailoverEndpointSelection = "failover"
+	defaultActiveMQEndpointSelection  = activeMQAllEndpointSelection
+
+	defaultActiveMQRetryCount    = 0
+	defaultActiveMQRetryInterval = 500 * time.Millisecond
+
+	activeMQHTTPScheme  = "http"
+	activeMQHTTPSScheme = "https"
+
+	activeMQHTTPProtocol    = "http"
+	activeMQStompProtocol   = "stomp"
+	defaultActiveMQProtocol = activeMQHTTPProtocol
+	// activeMQStompTimeout bounds a STOMP connection when neither the context nor the HTTP client set a deadline
+	activeMQStompTimeout = 30 * time.Second
+
+	// activeMQOAuthAuthMode fetches bearer tokens with the OAuth2 client credentials flow
+	activeMQOAuthAuthMode authentication.Type = "oauth"
+	// activeMQTokenExpiryDelta is how long before their expiry OAuth2 access tokens are refreshed
+	activeMQTokenExpiryDelta = 30 * time.Second
+	// activeMQRequestIDHeader carries the ID shared by the requests of a poll, to trace it in the broker access logs
+	activeMQRequestIDHeader = "X-KEDA-Request-Id"
+	// activeMQSessionAuthMode logs in with the username and password on loginURL and sends the session cookie it returns
+	activeMQSessionAuthMode authentication.Type = "session"
+	// activeMQDigestAuthMode answers the HTTP digest challenges of the management endpoint with the username and password
+	activeMQDigestAuthMode authentication.Type = "digest"
+	// activeMQAWSSigV4AuthMode signs the requests with AWS Signature Version 4, set with awsSigV4 rather than authMode
+	activeMQAWSSigV4AuthMode authentication.Type = "awsSigV4"
 )
 
+var activeMQMetricNameReplacer = regexp.MustCompile(`[^a-zA-Z0-9.-]+`)
+
 var activeMQLog = logf.Log.WithName("activeMQ_scaler")
 
+// activeMQUserAgent identifies the scaler to the management endpoints
+var activeMQUserAgent = fmt.Sprintf("keda/%s activemq-scaler", version.Version)
+
+// activeMQRequestIDKey is the context key of the request ID of a poll
+type activeMQRequestIDKey struct{}
+
+var (
+	activeMQPollLabels = []string{"broker", "destination"}
+	activeMQPollErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "keda",
+			Subsystem: "activemq",
+			Name:      "poll_errors_total",
+			Help:      "Number of failed polls of the ActiveMQ management endpoints",
+		},
+		activeMQPollLabels,
+	)
+	activeMQPollLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "keda",
+			Subsystem: "activemq",
+			Name:      "poll_latency_seconds",
+			Help:      "Duration of the polls of the ActiveMQ management endpoints",
+			Buckets:   prometheus.DefBuckets,
+		},
+		activeMQPollLabels,
+	)
+)
+
+func init() {
+	// the controller-runtime registry is served by both the operator and the metrics adapter
+	ctrlmetrics.Registry.MustRegister(activeMQPollErrors, activeMQPollLatency)
+}
+
+// tlsParams returns the parsed TLS settings in the form authentication.ParseTLSConfig reads them
+func (m *activeMQMetadata) tlsParams() map[string]string {
+	params := map[string]string{
+		"ca":        m.ca,
+		"unsafeSsl": strconv.FormatBool(m.unsafeSsl),
+	}
+	if m.enableTLS {
+		params["tls"] = "enable"
+		params["cert"] = m.cert
+		params["key"] = m.key
+	}
+	return params
+}
+
 // NewActiveMQScaler creates a new activeMQ Scaler
 func NewActiveMQScaler(config *ScalerConfig) (Scaler, error) {
-	meta, err := parseActiveMQMetadata(config)
+	meta, tlsConfig, err := parseActiveMQConfig(config)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing ActiveMQ metadata: %s", err)
+		return nil, err
+	}
+	if meta.unsafeSsl {
+		activeMQLog.Info("TLS certificate verification of the ActiveMQ management endpoint is disabled (unsafeSsl), this should not be used in production", "managementEndpoint", meta.managementEndpoint)
 	}
-	httpClient := kedautil.CreateHTTPClient(config.GlobalHTTPTimeout, false)
 
-	return &activeMQScaler{
+	options := []kedautil.HTTPClientOption{kedautil.WithProxy(meta.proxyURL)}
+	if meta.http2 {
+		options = append(options, kedautil.WithHTTP2())
+	}
+	if meta.idleConnTimeout > 0 {
+		options = append(options, kedautil.WithIdleConnTimeout(meta.idleConnTimeout))
+	}
+	if meta.keepAlive > 0 {
+		options = append(options, kedautil.WithKeepAlive(meta.keepAlive))
+	}
+	httpClient := kedautil.CreateHTTPClientWithTLS(meta.timeout, tlsConfig, options...)
+
+	scaler := &activeMQScaler{
 		metadata:   meta,
 		httpClient: httpClient,
-	}, nil
+	}
+	scaler.fetcher = newActiveMQMetricFetcher(scaler)
+	if meta.scheme == activeMQHTTPSScheme {
+		scaler.tlsConfig = tlsConfig
+	}
+	if meta.authMode == activeMQOAuthAuthMode {
+		scaler.tokenManager = newActiveMQTokenManager(meta, httpClient)
+	}
+	if meta.authMode == activeMQSessionAuthMode {
+		// the jar holds the session cookie, the reads send it along like a browser would
+		if httpClient.Jar, err = cookiejar.New(nil); err != nil {
+			return nil, err
+		}
+		scaler.sessionManager = &activeMQSessionManager{meta: meta, httpClient: httpClient}
+	}
+	if meta.authMode == activeMQDigestAuthMode {
+		// wrapping the transport keeps its TLS and proxy settings, and the retries go through the handshake too
+		scaler.digestTransport = newActiveMQDigestTransport(httpClient.Transport, meta.username, meta.password)
+		httpClient.Transport = scaler.digestTransport
+	}
+	if meta.authMode == activeMQAWSSigV4AuthMode {
+		// requests are signed last, after all their headers have been set
+		httpClient.Transport = authentication.NewAWSSigV4RoundTripper(meta.awsSigV4, httpClient.Transport)
+	}
+	activeMQLog.V(1).Info("Created ActiveMQ scaler", meta.logValues()...)
+	return scaler, nil
 }
 
-func parseActiveMQMetadata(config *ScalerConfig) (*activeMQMetadata, error) {
-	meta := activeMQMetadata{}
+// ValidateActiveMQMetadata checks the metadata, authentication parameters and TLS settings of an ActiveMQ trigger
+// without contacting the broker, so that a bad configuration can be rejected when the ScaledObject is applied
+func ValidateActiveMQMetadata(config *ScalerConfig) error {
+	_, _, err := parseActiveMQConfig(config)
+	return err
+}
 
-	if val, ok := config.TriggerMetadata["restAPITemplate"]; ok && val != "" {
-		meta.restAPITemplate = config.TriggerMetadata["restAPITemplate"]
-		var err error
-		if meta, err = getRestAPIParameters(meta); err != nil {
-			return nil, fmt.Errorf("can't parse restAPITemplate : %s ", err)
-		}
-	} else {
-		meta.restAPITemplate = defaultActiveMQRestAPITemplate
-		if config.TriggerMetadata["managementEndpoint"] == "" {
-			return nil, errors.New("no management endpoint given")
-		}
-		meta.managementEndpoint = config.TriggerMetadata["managementEndpoint"]
+// target returns the target of the metric, the broker usage target in the broker usage mode
+func (m *activeMQMetadata) target() int {
+	if m.brokerUsage != nil {
+		return m.brokerUsageTarget
+	}
+	return m.targetQueueSize
+}
 
-		if config.TriggerMetadata["destinationName"] == "" {
-			return nil, errors.New("no destination name given")
+// bracketValue returns the value reported for the scalingBrackets, the replicas of the highest bracket reached
+// times the target, so that the HPA divides it back to the replica count. Below the first bracket it is 0.
+func (m *activeMQMetadata) bracketValue(value float64) float64 {
+	replicas := 0
+	for _, bracket := range m.scalingBrackets {
+		if value < bracket.threshold {
+			break
 		}
-		meta.destinationName = config.TriggerMetadata["destinationName"]
+		replicas = bracket.replicas
+	}
+	return float64(replicas * m.target())
+}
 
-		if config.TriggerMetadata["brokerName"] == "" {
-			return nil, errors.New("no broker name given")
-		}
-		meta.brokerName = config.TriggerMetadata["brokerName"]
+// logValues returns the effective configuration as key, value pairs for logging. Passwords, tokens, client
+// secrets and keys are left out, only the names of the custom headers are given as their values may be secrets.
+func (m *activeMQMetadata) logValues() []interface{} {
+	target := m.target()
+	headers := make([]string, 0, len(m.customHeaders))
+	for name := range m.customHeaders {
+		headers = append(headers, name)
 	}
+	sort.Strings(headers)
 
-	if val, ok := config.TriggerMetadata["targetQueueSize"]; ok {
-		queueSize, err := strconv.Atoi(val)
-		if err != nil {
-			return nil, fmt.Errorf("invalid targetQueueSize - must be an integer")
+	values := []interface{}{
+		"managementEndpoints", m.managementEndpoints,
+		"brokerName", m.brokerName,
+		"brokerType", m.brokerType,
+		"destinationName", m.destinationName,
+		"destinationType", m.destinationType,
+		"targetAttribute", m.targetAttribute,
+		"target", target,
+		"activationTarget", m.activationTargetQueueSize,
+		"metricName", m.metricName,
+		"metricType", m.metricType,
+		"protocol", m.protocol,
+		"tls", m.scheme == activeMQHTTPSScheme,
+		"clientCertificate", m.enableTLS,
+		"unsafeSsl", m.unsafeSsl,
+		"authMode", m.authMode,
+		"customHeaders", headers,
+	}
+	if m.proxyURL != nil {
+		values = append(values, "proxyURL", m.proxyURL.Redacted())
+	}
+	if m.pollJitter > 0 {
+		values = append(values, "pollJitter", m.pollJitter)
+	}
+	if m.http2 {
+		values = append(values, "http2", m.http2)
+	}
+	for name, version := range activeMQTLSVersions {
+		if m.minTLSVersion == version {
+			values = append(values, "minTLSVersion", name)
 		}
+	}
+	if m.idleConnTimeout > 0 {
+		values = append(values, "idleConnTimeout", m.idleConnTimeout)
+	}
+	if m.keepAlive > 0 {
+		values = append(values, "keepAlive", m.keepAlive)
+	}
+	if m.jolokiaProxyTarget != nil {
+		values = append(values, "jolokiaProxyTarget", m.jolokiaProxyTarget.URL)
+	}
+	if m.maxQueueSizeCap > 0 {
+		values = append(values, "maxQueueSizeCap", m.maxQueueSizeCap)
+	}
+	if m.metricMultiplier > 0 {
+		values = append(values, "metricMultiplier", m.metricMultiplier)
+	}
+	if len(m.successStatusCodes) != 1 || m.successStatusCodes[0] != http.StatusOK {
+		values = append(values, "successStatusCodes", m.successStatusCodes)
+	}
+	if m.subscriptionName != "" {
+		values = append(values, "clientId", m.subscriptionClientID, "subscriptionName", m.subscriptionName)
+	}
+	if m.tokenFile != "" {
+		values = append(values, "tokenFile", m.tokenFile)
+	}
+	if m.valueJSONPath != "" {
+		values = append(values, "valueJSONPath", m.valueJSONPath)
+	}
+	if m.loginURL != "" {
+		values = append(values, "loginURL", m.loginURL)
+	}
+	if m.treatMissingAsZero {
+		values = append(values, "treatMissingAsZero", m.treatMissingAsZero)
+	}
+	if m.errorBehavior != defaultActiveMQErrorBehavior {
+		values = append(values, "errorBehavior", m.errorBehavior)
+	}
+	if m.messagesPerConsumer {
+		values = append(values, "messagesPerConsumer", m.messagesPerConsumer)
+	}
+	if m.pendingBytes {
+		values = append(values, "pendingBytes", m.pendingBytes)
+	}
+	if m.sampleCount > 1 {
+		values = append(values, "sampleCount", m.sampleCount, "sampleInterval", m.sampleInterval)
+	}
+	if m.activationOperator != defaultActiveMQActivationOperator {
+		values = append(values, "activationOperator", m.activationOperator)
+	}
+	if m.jolokiaVersion != 0 {
+		values = append(values, "jolokiaVersion", m.jolokiaVersion)
+	} else if m.detectJolokiaVersion {
+		values = append(values, "jolokiaVersion", activeMQJolokiaVersionAuto)
+	}
+	return values
+}
 
-		meta.targetQueueSize = queueSize
-	} else {
-		meta.targetQueueSize = defaultTargetQueueSize
+// activeMQTokenManager fetches OAuth2 access tokens with the client credentials flow
+// and caches them across polls until shortly before they expire
+type activeMQTokenManager struct {
+	config     clientcredentials.Config
+	httpClient *http.Client
+
+	lock  sync.Mutex
+	token *oauth2.Token
+}
+
+func newActiveMQTokenManager(meta *activeMQMetadata, httpClient *http.Client) *activeMQTokenManager {
+	return &activeMQTokenManager{
+		config: clientcredentials.Config{
+			ClientID:     meta.clientID,
+			ClientSecret: meta.clientSecret,
+			TokenURL:     meta.oauthTokenURL,
+			Scopes:       meta.scopes,
+		},
+		httpClient: httpClient,
 	}
+}
 
-	if val, ok := config.AuthParams["username"]; ok && val != "" {
-		meta.username = val
-	} else if val, ok := config.TriggerMetadata["username"]; ok && val != "" {
-		username := val
+// getToken returns the cached access token, fetching a new one if there is none or it is about to expire
+func (m *activeMQTokenManager) getToken(ctx context.Context) (string, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 
-		if val, ok := config.ResolvedEnv[username]; ok && val != "" {
-			meta.username = val
-		} else {
-			meta.username = username
-		}
+	if m.token != nil && (m.token.Expiry.IsZero() || time.Until(m.token.Expiry) > activeMQTokenExpiryDelta) {
+		return m.token.AccessToken, nil
 	}
 
-	if meta.username == "" {
-		return nil, fmt.Errorf("username cannot be empty")
+	// fetch the token with the scaler's client so the TLS settings apply to the token endpoint too
+	token, err := m.config.Token(context.WithValue(ctx, oauth2.HTTPClient, m.httpClient))
+	if err != nil {
+		return "", fmt.Errorf("error fetching OAuth2 access token: %s", err)
 	}
+	m.token = token
+	return token.AccessToken, nil
+}
 
-	if val, ok := config.AuthParams["password"]; ok && val != "" {
-		meta.password = val
-	} else if val, ok := config.TriggerMetadata["password"]; ok && val != "" {
-		password := val
+// invalidate drops the cached access token, e.g. after it was rejected by the management endpoint
+func (m *activeMQTokenManager) invalidate() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.token = nil
+}
 
-		if val, ok := config.ResolvedEnv[password]; ok && val != "" {
-			meta.password = val
-		} else {
-			meta.password = password
-		}
+// activeMQSessionManager logs in on loginURL with a form post of the username and password. The session cookie
+// the login returns is kept in the jar of the HTTP client and reused until the management endpoint rejects it.
+type activeMQSessionManager struct {
+	meta       *activeMQMetadata
+	httpClient *http.Client
+
+	lock     sync.Mutex
+	loggedIn bool
+}
+
+// login logs in unless a previous login is still valid
+func (m *activeMQSessionManager) login(ctx context.Context) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.loggedIn {
+		return nil
 	}
 
-	if meta.password == "" {
-		return nil, fmt.Errorf("password cannot be empty")
+	form := url.Values{"username": {m.meta.username}, "password": {m.meta.password}}
+	req, err := http.NewRequestWithContext(ctx, "POST", m.meta.loginURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
 	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	meta.metricName = GenerateMetricNameWithIndex(config.ScalerIndex, kedautil.NormalizeString(fmt.Sprintf("activemq-%s", meta.destinationName)))
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error logging in to ActiveMQ: %s", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
 
-	meta.scalerIndex = config.ScalerIndex
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("ActiveMQ login failed with status %d, check the username and password", resp.StatusCode)
+	}
+	if len(m.httpClient.Jar.Cookies(req.URL)) == 0 {
+		return errors.New("ActiveMQ login didn't return a session cookie")
+	}
+	m.loggedIn = true
+	return nil
+}
 
-	return &meta, nil
+// invalidate forces a new login, e.g. after the session was rejected by the management endpoint
+func (m *activeMQSessionManager) invalidate() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.loggedIn = false
 }
 
 func (s *activeMQScaler) IsActive(ctx context.Context) (bool, error) {
-	queueSize, err := s.getQueueMessageCount(ctx)
+	_, isActive, err := s.GetMetricsAndActivity(ctx, s.metadata.metricName)
 	if err != nil {
 		activeMQLog.Error(err, "Unable to access activeMQ management endpoint", "managementEndpoint", s.metadata.managementEndpoint)
 		return false, err
 	}
 
-	return queueSize > 0, nil
+	return isActive, nil
 }
 
-// getRestAPIParameters parse restAPITemplate to provide managementEndpoint, brokerName, destinationName
-func getRestAPIParameters(meta activeMQMetadata) (activeMQMetadata, error) {
-	u, err := url.ParseRequestURI(meta.restAPITemplate)
-	if err != nil {
-		return meta, fmt.Errorf("unable to parse ActiveMQ restAPITemplate: %s", err)
+// activeMQPoll is a poll in flight with cacheTTL, done is closed once value and err are set
+type activeMQPoll struct {
+	done  chan struct{}
+	value float64
+	err   error
+}
+
+// getDestinationMetric reads the configured target attribute of the destination, or returns the last
+// value read if it is fresher than cacheTTL
+func (s *activeMQScaler) getDestinationMetric(ctx context.Context) (float64, error) {
+	if s.metadata.cacheTTL <= 0 {
+		return s.pollDestinationMetric(ctx)
+	}
+
+	// the lock isn't held while polling, which may wait between the samples, callers arriving meanwhile share the poll
+	s.cacheLock.Lock()
+	if !s.cachedAt.IsZero() && time.Since(s.cachedAt) < s.metadata.cacheTTL {
+		defer s.cacheLock.Unlock()
+		return s.cachedValue, nil
+	}
+	if poll := s.cachePoll; poll != nil {
+		s.cacheLock.Unlock()
+		select {
+		case <-ctx.Done():
+			return -1, ctx.Err()
+		case <-poll.done:
+			return poll.value, poll.err
+		}
+	}
+	poll := &activeMQPoll{done: make(chan struct{})}
+	s.cachePoll = poll
+	s.cacheLock.Unlock()
+
+	poll.value, poll.err = s.pollDestinationMetric(ctx)
+	s.cacheLock.Lock()
+	s.cachePoll = nil
+	if poll.err == nil {
+		s.cachedValue = poll.value
+		s.cachedAt = time.Now()
 	}
+	s.cacheLock.Unlock()
+	close(poll.done)
+	return poll.value, poll.err
+}
 
-	meta.managementEndpoint = u.Host
-	splitURL := strings.Split(strings.Split(u.Path, ":")[1], "/")[0] // This returns : type=Broker,brokerName=<<brokerName>>,destinationType=Queue,destinationName=<<destinationName>>
-	replacer := strings.NewReplacer(",", "&")
-	v, err := url.ParseQuery(replacer.Replace(splitURL)) // This returns a map with key: string types and element type [] string. : map[brokerName:[<<brokerName>>] destinationName:[<<destinationName>>] destinationType:[Queue] type:[Broker]]
+// pollDestinationMetric reads the configured target attribute of the destination from the management endpoints
+func (s *activeMQScaler) pollDestinationMetric(ctx context.Context) (float64, error) {
+	// a random delay spreads the polls of the scalers reading the same broker, which are otherwise in step
+	if s.metadata.pollJitter > 0 {
+		select {
+		case <-ctx.Done():
+			return -1, ctx.Err()
+		case <-time.After(activeMQJitter(s.metadata.pollJitter)):
+		}
+	}
+
+	// all the requests of the poll share a request ID
+	requestID := uuid.New().String()
+	ctx = context.WithValue(ctx, activeMQRequestIDKey{}, requestID)
+	start := time.Now()
+	sample, err := s.getSample(ctx)
+	activeMQPollLatency.WithLabelValues(s.brokerName(), s.metadata.destinationName).Observe(time.Since(start).Seconds())
 	if err != nil {
-		return meta, fmt.Errorf("unable to parse ActiveMQ restAPITemplate: %s", err)
+		activeMQPollErrors.WithLabelValues(s.brokerName(), s.metadata.destinationName).Inc()
+		activeMQLog.V(1).Info("ActiveMQ poll failed", "requestID", requestID, "error", err.Error())
+		return -1, err
 	}
 
-	if len(v["destinationName"][0]) == 0 {
-		return meta, errors.New("no destinationName is given")
+	metricValue := sample.value
+	if activeMQAttributes[s.metadata.targetAttribute].cumulative {
+		metricValue = s.getRate(sample)
+		// with sampleCount the poll takes more samples sampleInterval apart, the rate is averaged over all of them.
+		// The samples that would be taken after the deadline of the caller are left out.
+		for i := 1; i < s.metadata.sampleCount; i++ {
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= s.metadata.sampleInterval {
+				activeMQLog.V(1).Info("Stopping the ActiveMQ sampling before the deadline", "requestID", requestID, "samples", i)
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return -1, ctx.Err()
+			case <-time.After(s.metadata.sampleInterval):
+			}
+			if sample, err = s.getSample(ctx); err != nil {
+				activeMQPollErrors.WithLabelValues(s.brokerName(), s.metadata.destinationName).Inc()
+				activeMQLog.V(1).Info("ActiveMQ poll failed", "requestID", requestID, "error", err.Error())
+				return -1, err
+			}
+			metricValue = s.getRate(sample)
+		}
 	}
-	meta.destinationName = v["destinationName"][0]
 
-	if len(v["brokerName"][0]) == 0 {
-		return meta, fmt.Errorf("no brokerName given: %s", meta.restAPITemplate)
+	activeMQLog.V(1).Info(fmt.Sprintf("ActiveMQ scaler: Providing metrics based on current %s %g target %d", s.metadata.targetAttribute, metricValue, s.metadata.targetQueueSize), "requestID", requestID)
+	return metricValue, nil
+}
+
+// getSample reads the target attribute from every management endpoint and aggregates the results.
+// Unreachable endpoints fail the whole poll unless skipUnreachableEndpoints is set.
+func (s *activeMQScaler) getSample(ctx context.Context) (activeMQSample, error) {
+	if s.metadata.discoverBroker {
+		if unreachable, err := s.discoverBrokerName(ctx); err != nil {
+			if unreachable {
+				err = newUnreachableError(err)
+			}
+			return activeMQSample{}, err
+		}
+	}
+
+	if s.metadata.endpointSelection == activeMQFailoverEndpointSelection {
+		return s.getFailoverSample(ctx)
+	}
+
+	samples := make([]activeMQSample, 0, len(s.metadata.managementEndpoints))
+	for _, endpoint := range s.metadata.managementEndpoints {
+		sample, unreachable, err := s.getEndpointSample(ctx, endpoint)
+		if err != nil {
+			if unreachable {
+				err = newUnreachableError(err)
+			}
+			if !unreachable || !s.metadata.skipUnreachableEndpoints {
+				return activeMQSample{}, err
+			}
+			activeMQLog.Error(err, "Skipping unreachable ActiveMQ management endpoint", "managementEndpoint", endpoint)
+			continue
+		}
+		samples = append(samples, sample)
 	}
-	meta.brokerName = v["brokerName"][0]
 
-	return meta, nil
+	if len(samples) == 0 {
+		return activeMQSample{}, newUnreachableError(errors.New("none of the ActiveMQ management endpoints is reachable"))
+	}
+	return aggregateActiveMQSamples(samples, s.metadata.aggregation), nil
 }
 
-func (s *activeMQScaler) getMonitoringEndpoint() (string, error) {
-	var buf bytes.Buffer
-	endpoint := map[string]string{
-		"ManagementEndpoint": s.metadata.managementEndpoint,
-		"BrokerName":         s.metadata.brokerName,
-		"DestinationName":    s.metadata.destinationName,
+// getFailoverSample tries the management endpoints in order and returns the first successful read. Each poll
+// starts over from the first endpoint, or with sticky from the endpoint that answered the previous poll.
+func (s *activeMQScaler) getFailoverSample(ctx context.Context) (activeMQSample, error) {
+	s.endpointLock.Lock()
+	start := s.activeEndpoint
+	s.endpointLock.Unlock()
+
+	var errs []string
+	allUnreachable := true
+	endpoints := s.metadata.managementEndpoints
+	for i := range endpoints {
+		index := (start + i) % len(endpoints)
+		sample, unreachable, err := s.getEndpointSample(ctx, endpoints[index])
+		if err != nil {
+			activeMQLog.V(1).Info("Failing over from ActiveMQ management endpoint", "managementEndpoint", endpoints[index], "error", err.Error())
+			errs = append(errs, fmt.Sprintf("%s: %s", endpoints[index], err))
+			allUnreachable = allUnreachable && unreachable
+			continue
+		}
+		if s.metadata.sticky {
+			s.endpointLock.Lock()
+			s.activeEndpoint = index
+			s.endpointLock.Unlock()
+		}
+		return sample, nil
 	}
-	template, err := template.New("monitoring_endpoint").Parse(defaultActiveMQRestAPITemplate)
-	if err != nil {
-		return "", fmt.Errorf("error parsing template: %s", err)
+	err := fmt.Errorf("all ActiveMQ management endpoints failed: %s", strings.Join(errs, "; "))
+	if allUnreachable {
+		return activeMQSample{}, newUnreachableError(err)
 	}
-	err = template.Execute(&buf, endpoint)
-	if err != nil {
-		return "", fmt.Errorf("error executing template: %s", err)
+	return activeMQSample{}, err
+}
+
+// getEndpointSample reads the sample from one management endpoint with the fetcher of the configured protocol,
+// it reports whether a failure means the endpoint is unreachable
+func (s *activeMQScaler) getEndpointSample(ctx context.Context, endpoint string) (activeMQSample, bool, error) {
+	fetcher := s.fetcher
+	if fetcher == nil {
+		fetcher = newActiveMQMetricFetcher(s)
 	}
-	monitoringEndpoint := buf.String()
-	return monitoringEndpoint, nil
+	return fetcher.Fetch(ctx, endpoint)
 }
 
-func (s *activeMQScaler) getQueueMessageCount(ctx context.Context) (int, error) {
-	var monitoringInfo *activeMQMonitoring
-	var queueMessageCount int
+// brokerName returns the brokerName of the trigger, or the one discovered on the management endpoint when it is
+// left out, empty until it is found. The parsed metadata is never changed.
+func (s *activeMQScaler) brokerName() string {
+	if !s.metadata.discoverBroker {
+		return s.metadata.brokerName
+	}
+	s.brokerLock.Lock()
+	defer s.brokerLock.Unlock()
+	return s.discoveredBrokerName
+}
 
-	client := s.httpClient
-	url, err := s.getMonitoringEndpoint()
-	if err != nil {
-		return -1, err
+// activeMQWildcardToRegexp converts an ActiveMQ wildcard destination name, where * matches a single name segment
+// and > matches all the remaining segments, into a regular expression
+func activeMQWildcardToRegexp(pattern string) string {
+	segments := strings.Split(pattern, ".")
+	for i, segment := range segments {
+		switch segment {
+		case "*":
+			segments[i] = `[^.]+`
+		case ">":
+			segments[i] = `.+`
+		default:
+			segments[i] = regexp.QuoteMeta(segment)
+		}
+	}
+	return strings.Join(segments, `\.`)
+}
+
+// isActiveMQWildcard reports whether destinationName uses the ActiveMQ wildcard syntax
+func isActiveMQWildcard(destinationName string) bool {
+	for _, segment := range strings.Split(destinationName, ".") {
+		if segment == "*" || segment == ">" {
+			return true
+		}
 	}
+	return false
+}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return -1, err
+// aggregateActiveMQSamples combines the samples of several brokers, keeping the most recent timestamp
+func aggregateActiveMQSamples(samples []activeMQSample, aggregation string) activeMQSample {
+	result := samples[0]
+	for _, sample := range samples[1:] {
+		if aggregation == activeMQMaxAggregation {
+			result.value = math.Max(result.value, sample.value)
+		} else {
+			result.value += sample.value
+		}
+		if sample.timestamp > result.timestamp {
+			result.timestamp = sample.timestamp
+		}
 	}
+	if aggregation == activeMQAvgAggregation {
+		result.value /= float64(len(samples))
+	}
+	return result
+}
 
-	// Add HTTP Auth and Headers
-	req.SetBasicAuth(s.metadata.username, s.metadata.password)
-	req.Header.Set("Content-Type", "application/json")
+// withRetries runs a single management endpoint request, retrying connection errors and 5xx responses
+// up to retryCount times with a jittered exponential backoff starting at retryInterval.
+// It reports whether the last failure means the endpoint is unreachable.
+func (s *activeMQScaler) withRetries(ctx context.Context, endpoint string, request func() (bool, error)) (bool, error) {
+	// the request decides what is worth retrying, as STOMP and decoding failures count too
+	policy := kedautil.RetryPolicy{Count: s.metadata.retryCount, Interval: s.metadata.retryInterval}
+	var lastErr error
+	return policy.Retry(ctx, func(attempt int) (bool, error) {
+		if attempt > 0 {
+			activeMQLog.V(1).Info("Retrying ActiveMQ management endpoint request", "managementEndpoint", endpoint, "attempt", attempt, "error", lastErr.Error())
+		}
+		retryable, err := request()
+		lastErr = err
+		return retryable, err
+	})
+}
 
-	resp, err := client.Do(req)
+// readActiveMQTokenFile returns the bearer token stored in a file, such as a projected service account token
+func readActiveMQTokenFile(path string) (string, error) {
+	content, err := ioutil.ReadFile(path)
 	if err != nil {
-		return -1, err
+		return "", fmt.Errorf("error reading the ActiveMQ bearer token from tokenFile: %s", err)
+	}
+	token := strings.TrimSpace(string(content))
+	if token == "" {
+		return "", fmt.Errorf("the ActiveMQ tokenFile %s is empty", path)
 	}
+	return token, nil
+}
 
-	defer resp.Body.Close()
+// getCredentialsDescription names the credentials of the configured authMode for error messages
+func (s *activeMQScaler) getCredentialsDescription() string {
+	switch s.metadata.authMode {
+	case authentication.BearerAuthType:
+		if s.metadata.tokenFile != "" {
+			return "bearer token read from tokenFile"
+		}
+		return "bearer token"
+	case activeMQOAuthAuthMode:
+		return "OAuth2 client credentials and scopes"
+	case activeMQSessionAuthMode:
+		return "username and password, the session may have expired"
+	case activeMQAWSSigV4AuthMode:
+		return "AWS credentials, region and service the requests are signed for"
+	default:
+		return "username and password"
+	}
+}
 
-	if err := json.NewDecoder(resp.Body).Decode(&monitoringInfo); err != nil {
-		return -1, err
+// getRate turns successive samples of a cumulative counter into a rate per second, using the Jolokia
+// response timestamps. The first sample only sets the baseline, so the rate is 0 until a second poll.
+// The baseline moves forward once rateWindow has elapsed, which keeps short polling intervals from
+// producing noisy deltas. With sampleCount above 1 the average of the last sampleCount rates is returned.
+func (s *activeMQScaler) getRate(sample activeMQSample) float64 {
+	s.rateLock.Lock()
+	defer s.rateLock.Unlock()
+
+	// no baseline yet or the counter was reset by a broker restart
+	if s.rateBaseline == nil || sample.value < s.rateBaseline.value {
+		s.rateBaseline = &sample
+		s.lastRate = 0
+		s.rateSamples = s.rateSamples[:0]
+		s.nextRateSample = 0
+		return s.lastRate
 	}
-	if resp.StatusCode == 200 && monitoringInfo.Status == 200 {
-		queueMessageCount = monitoringInfo.MsgCount
-	} else {
-		return -1, fmt.Errorf("ActiveMQ management endpoint response error code : %d %d", resp.StatusCode, monitoringInfo.Status)
+
+	elapsed := sample.timestamp - s.rateBaseline.timestamp
+	if elapsed <= 0 {
+		return s.getSmoothedRate()
 	}
 
-	activeMQLog.V(1).Info(fmt.Sprintf("ActiveMQ scaler: Providing metrics based on current queue size %d queue size limit %d", queueMessageCount, s.metadata.targetQueueSize))
+	s.lastRate = (sample.value - s.rateBaseline.value) / float64(elapsed)
+	if time.Duration(elapsed)*time.Second >= s.metadata.rateWindow {
+		s.rateBaseline = &sample
+	}
 
-	return queueMessageCount, nil
+	if s.metadata.sampleCount > 1 {
+		if len(s.rateSamples) < s.metadata.sampleCount {
+			s.rateSamples = append(s.rateSamples, s.lastRate)
+		} else {
+			s.rateSamples[s.nextRateSample] = s.lastRate
+		}
+		s.nextRateSample = (s.nextRateSample + 1) % s.metadata.sampleCount
+	}
+	return s.getSmoothedRate()
+}
+
+// getSmoothedRate returns the average of the rates in the ring buffer, the last rate when it is empty.
+// The caller must hold rateLock.
+func (s *activeMQScaler) getSmoothedRate() float64 {
+	if len(s.rateSamples) == 0 {
+		return s.lastRate
+	}
+	var sum float64
+	for _, rate := range s.rateSamples {
+		sum += rate
+	}
+	return sum / float64(len(s.rateSamples))
 }
 
 // GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
 func (s *activeMQScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
-	targetMetricValue := resource.NewQuantity(int64(s.metadata.targetQueueSize), resource.DecimalSI)
+	targetMetricValue := resource.NewQuantity(int64(s.metadata.target()), resource.DecimalSI)
 	externalMetric := &v2beta2.ExternalMetricSource{
 		Metric: v2beta2.MetricIdentifier{
 			Name: s.metadata.metricName,
 		},
 		Target: v2beta2.MetricTarget{
-			Type:         v2beta2.AverageValueMetricType,
-			AverageValue: targetMetricValue,
+			Type: s.metadata.metricType,
 		},
 	}
+	// an AverageValue target is divided by the number of replicas, a Value target compared to the metric as is
+	if s.metadata.metricType == v2beta2.ValueMetricType {
+		externalMetric.Target.Value = targetMetricValue
+	} else {
+		externalMetric.Target.AverageValue = targetMetricValue
+	}
 	metricSpec := v2beta2.MetricSpec{
 		External: externalMetric, Type: externalMetricType,
 	}
@@ -259,20 +970,148 @@ func (s *activeMQScaler) GetMetricSpecForScaling(context.Context) []v2beta2.Metr
 }
 
 func (s *activeMQScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
-	queueSize, err := s.getQueueMessageCount(ctx)
+	metrics, _, err := s.GetMetricsAndActivity(ctx, metricName)
+	return metrics, err
+}
+
+// GetMetricsAndActivity returns the metric value and whether the scaler is active from a single read of the
+// management endpoints, it implements MetricsAndActivityScaler so that the scalers cache polls the broker once
+func (s *activeMQScaler) GetMetricsAndActivity(ctx context.Context, metricName string) ([]external_metrics.ExternalMetricValue, bool, error) {
+	metricValue, err := s.getDestinationMetric(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("error inspecting ActiveMQ queue size: %s", err)
+		err = fmt.Errorf("error inspecting ActiveMQ %s: %w", s.getJolokiaAttribute(), err)
+		if metricValue, err = s.applyErrorBehavior(err); err != nil {
+			return nil, false, err
+		}
+	} else {
+		s.lastKnownLock.Lock()
+		s.lastKnownValue = &metricValue
+		s.lastKnownLock.Unlock()
+	}
+
+	// activity is decided on the actual value, only the value reported to the HPA is multiplied and capped
+	reportedValue := metricValue
+	if s.metadata.metricMultiplier > 0 {
+		reportedValue *= s.metadata.metricMultiplier
+	}
+	if s.metadata.maxQueueSizeCap > 0 && reportedValue > s.metadata.maxQueueSizeCap {
+		activeMQLog.V(1).Info("Capping the ActiveMQ metric value", "value", metricValue, "maxQueueSizeCap", s.metadata.maxQueueSizeCap)
+		reportedValue = s.metadata.maxQueueSizeCap
+	}
+	if s.metadata.scalingBrackets != nil {
+		reportedValue = s.metadata.bracketValue(metricValue)
+		activeMQLog.V(1).Info("Mapping the ActiveMQ metric value through the scalingBrackets", "value", metricValue, "reportedValue", reportedValue)
 	}
 
 	metric := external_metrics.ExternalMetricValue{
 		MetricName: metricName,
-		Value:      *resource.NewQuantity(int64(queueSize), resource.DecimalSI),
+		Value:      *resource.NewMilliQuantity(int64(reportedValue*1000), resource.DecimalSI),
 		Timestamp:  metav1.Now(),
 	}
 
-	return []external_metrics.ExternalMetricValue{metric}, nil
+	return []external_metrics.ExternalMetricValue{metric}, s.isActive(metricValue, time.Now()), nil
+}
+
+// LastMetricValue returns the last value read from the management endpoints, before metricMultiplier, maxQueueSizeCap
+// and scalingBrackets are applied, so it may differ from the value reported to the HPA
+func (s *activeMQScaler) LastMetricValue() (float64, bool) {
+	s.lastKnownLock.Lock()
+	defer s.lastKnownLock.Unlock()
+	if s.lastKnownValue == nil {
+		return 0, false
+	}
+	return *s.lastKnownValue, true
 }
 
+// applyErrorBehavior returns the value to report in place of the error with the lastKnown and zero errorBehavior, when
+// the management endpoints are unreachable. Other errors, such as rejected credentials, are always returned, as is
+// the error with lastKnown when no value has been read yet.
+func (s *activeMQScaler) applyErrorBehavior(err error) (float64, error) {
+	if !errors.Is(err, ErrUnreachable) {
+		return 0, err
+	}
+	switch s.metadata.errorBehavior {
+	case activeMQZeroErrorBehavior:
+		activeMQLog.Error(err, "Reporting 0 for the unreachable ActiveMQ management endpoints", "errorBehavior", s.metadata.errorBehavior)
+		return 0, nil
+	case activeMQLastKnownErrorBehavior:
+		s.lastKnownLock.Lock()
+		defer s.lastKnownLock.Unlock()
+		if s.lastKnownValue == nil {
+			return 0, err
+		}
+		activeMQLog.Error(err, "Reporting the last known value for the unreachable ActiveMQ management endpoints", "errorBehavior", s.metadata.errorBehavior, "value", *s.lastKnownValue)
+		return *s.lastKnownValue, nil
+	default:
+		return 0, err
+	}
+}
+
+// isActive reports whether the metric value compares to the activation threshold with the activationOperator, above
+// it by default. With emptyQueueStabilization the scaler only becomes inactive once the comparison has failed for
+// that long, a scaler that was never active since its creation stays inactive.
+func (s *activeMQScaler) isActive(metricValue float64, now time.Time) bool {
+	s.activityLock.Lock()
+	defer s.activityLock.Unlock()
+
+	if compareActiveMQActivation(metricValue, s.metadata.activationOperator, s.metadata.activationTargetQueueSize) {
+		s.activeAt = now
+		return true
+	}
+	return !s.activeAt.IsZero() && now.Sub(s.activeAt) < s.metadata.emptyQueueStabilization
+}
+
+// compareActiveMQActivation compares the metric value to the activation threshold with the activationOperator
+func compareActiveMQActivation(value float64, operator string, threshold float64) bool {
+	switch operator {
+	case activeMQGteActivationOperator:
+		return value >= threshold
+	case activeMQLtActivationOperator:
+		return value < threshold
+	case activeMQLteActivationOperator:
+		return value <= threshold
+	default:
+		return IsAboveActivationThreshold(value, threshold)
+	}
+}
+
+// Close releases the idle management endpoint connections and drops the cached access token, session, metric values
+// and detected Jolokia version.
+// STOMP connections only live for a single poll. Close can be called more than once.
 func (s *activeMQScaler) Close(context.Context) error {
+	if s.httpClient != nil {
+		s.httpClient.CloseIdleConnections()
+	}
+	if s.tokenManager != nil {
+		s.tokenManager.invalidate()
+	}
+	if s.sessionManager != nil {
+		s.sessionManager.invalidate()
+	}
+	if s.digestTransport != nil {
+		s.digestTransport.reset()
+	}
+
+	s.cacheLock.Lock()
+	s.cachedAt = time.Time{}
+	s.cacheLock.Unlock()
+
+	s.rateLock.Lock()
+	s.rateBaseline = nil
+	s.rateSamples = nil
+	s.nextRateSample = 0
+	s.rateLock.Unlock()
+
+	s.activityLock.Lock()
+	s.activeAt = time.Time{}
+	s.activityLock.Unlock()
+
+	s.versionLock.Lock()
+	s.detectedVersion = 0
+	s.versionLock.Unlock()
+
+	s.lastKnownLock.Lock()
+	s.lastKnownValue = nil
+	s.lastKnownLock.Unlock()
 	return nil
 }
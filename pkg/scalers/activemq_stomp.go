@@ -0,0 +1,50 @@
+package scalers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// activeMQStompFetcher reads the samples by browsing the queue over STOMP
+type activeMQStompFetcher struct {
+	scaler *activeMQScaler
+}
+
+// Fetch counts the messages of the queue
+func (f *activeMQStompFetcher) Fetch(ctx context.Context, endpoint string) (activeMQSample, bool, error) {
+	return f.scaler.getStompSample(ctx, endpoint)
+}
+
+// getStompSample counts the messages of the queue by browsing it over STOMP
+func (s *activeMQScaler) getStompSample(ctx context.Context, endpoint string) (activeMQSample, bool, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		timeout := s.httpClient.Timeout
+		if timeout <= 0 {
+			timeout = activeMQStompTimeout
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var count int
+	unreachable, err := s.withRetries(ctx, endpoint, func() (bool, error) {
+		client, err := dialActiveMQStomp(ctx, endpoint, s.tlsConfig, s.metadata.username, s.metadata.password)
+		if err != nil {
+			// errors reported by the broker, such as a failed login, are final
+			var netErr net.Error
+			return errors.As(err, &netErr) && ctx.Err() == nil, err
+		}
+		defer client.Close()
+
+		count, err = client.browseQueue(s.metadata.destinationName)
+		return false, err
+	})
+	if err != nil {
+		return activeMQSample{}, unreachable, fmt.Errorf("error browsing the ActiveMQ queue over STOMP: %s", err)
+	}
+	return activeMQSample{value: float64(count), timestamp: time.Now().Unix()}, false, nil
+}
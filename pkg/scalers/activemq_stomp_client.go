@@ -0,0 +1,270 @@
+package scalers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// stompMaxHeaderSize bounds the command and headers of a frame read from the broker
+	stompMaxHeaderSize = 64 * 1024
+	// stompMaxBodySize is how much of a frame body is kept, the rest is read and dropped. Only the frames are
+	// counted so their bodies don't matter, but they are sized by the peer and must not be allocated as such.
+	stompMaxBodySize = 64 * 1024
+)
+
+// stompFrame is a STOMP frame, only the first occurrence of a repeated header is kept as the protocol requires
+type stompFrame struct {
+	command string
+	headers map[string]string
+	body    []byte
+}
+
+var stompHeaderEscaper = strings.NewReplacer(`\`, `\\`, "\r", `\r`, "\n", `\n`, ":", `\c`)
+var stompHeaderUnescaper = strings.NewReplacer(`\\`, `\`, `\r`, "\r", `\n`, "\n", `\c`, ":")
+
+// activeMQStompClient is a minimal STOMP 1.2 client, just enough to browse an ActiveMQ queue and count its messages
+type activeMQStompClient struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	writer *bufio.Writer
+}
+
+// dialActiveMQStomp connects to the STOMP endpoint, over TLS if tlsConfig is set, and logs in. The connection
+// expires at the deadline of ctx, or after activeMQStompTimeout if it has none. A login rejected by the broker
+// is an ErrAuth.
+func dialActiveMQStomp(ctx context.Context, endpoint string, tlsConfig *tls.Config, login, passcode string) (*activeMQStompClient, error) {
+	host, _, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid STOMP endpoint %q - must be in the form host:port", endpoint)
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(activeMQStompTimeout)
+	}
+	dialer := net.Dialer{Deadline: deadline}
+	conn, err := dialer.DialContext(ctx, "tcp", endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if tlsConfig != nil {
+		config := tlsConfig.Clone()
+		if config.ServerName == "" {
+			config.ServerName = host
+		}
+		conn = tls.Client(conn, config)
+	}
+
+	client := &activeMQStompClient{
+		conn:   conn,
+		reader: bufio.NewReader(conn),
+		writer: bufio.NewWriter(conn),
+	}
+
+	headers := []string{"accept-version", "1.2", "host", host, "heart-beat", "0,0"}
+	if login != "" {
+		headers = append(headers, "login", login, "passcode", passcode)
+	}
+	if err := client.send("CONNECT", headers...); err != nil {
+		client.Close()
+		return nil, err
+	}
+	frame, err := client.receive()
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	if frame.command != "CONNECTED" {
+		client.Close()
+		if frame.command == "ERROR" {
+			// ActiveMQ answers a CONNECT with an ERROR when it rejects the credentials
+			return nil, newAuthError(stompFrameError(frame))
+		}
+		return nil, stompFrameError(frame)
+	}
+	return client, nil
+}
+
+// browseQueue counts the messages of a queue with a browsing subscription, which leaves the messages on the queue.
+// ActiveMQ delivers a copy of every message followed by a message with the browser:end header. As each message
+// is transferred this is only suitable for queues of a moderate size.
+func (c *activeMQStompClient) browseQueue(queue string) (int, error) {
+	if err := c.send("SUBSCRIBE", "id", "0", "destination", "/queue/"+queue, "ack", "auto", "browser", "true"); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for {
+		frame, err := c.receive()
+		if err != nil {
+			return 0, err
+		}
+		switch frame.command {
+		case "MESSAGE":
+			if frame.headers["browser"] == "end" {
+				return count, nil
+			}
+			count++
+		case "ERROR":
+			return 0, stompFrameError(frame)
+		}
+	}
+}
+
+// Close disconnects from the broker, without waiting for a receipt since nothing was sent that needs one
+func (c *activeMQStompClient) Close() error {
+	_ = c.send("DISCONNECT")
+	return c.conn.Close()
+}
+
+// send writes a frame without body, headers are given as key, value pairs
+func (c *activeMQStompClient) send(command string, headers ...string) error {
+	if err := writeStompFrame(c.writer, command, headers...); err != nil {
+		return err
+	}
+	return c.writer.Flush()
+}
+
+func (c *activeMQStompClient) receive() (*stompFrame, error) {
+	return readStompFrame(c.reader)
+}
+
+func writeStompFrame(w io.Writer, command string, headers ...string) error {
+	var buf bytes.Buffer
+	buf.WriteString(command)
+	buf.WriteByte('\n')
+	for i := 0; i+1 < len(headers); i += 2 {
+		// CONNECT frames are not escaped
+		key, value := headers[i], headers[i+1]
+		if command != "CONNECT" {
+			key, value = stompHeaderEscaper.Replace(key), stompHeaderEscaper.Replace(value)
+		}
+		fmt.Fprintf(&buf, "%s:%s\n", key, value)
+	}
+	buf.WriteByte('\n')
+	buf.WriteByte(0)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func readStompFrame(r *bufio.Reader) (*stompFrame, error) {
+	budget := stompMaxHeaderSize
+
+	// heart-beats and the optional EOLs after a frame are empty lines before the command
+	var command string
+	for command == "" {
+		line, err := readStompLine(r, &budget)
+		if err != nil {
+			return nil, err
+		}
+		command = line
+	}
+
+	frame := &stompFrame{command: command, headers: map[string]string{}}
+	for {
+		line, err := readStompLine(r, &budget)
+		if err != nil {
+			return nil, err
+		}
+		if line == "" {
+			break
+		}
+		kv := strings.SplitN(line, ":", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid STOMP header %q", line)
+		}
+		key, value := kv[0], kv[1]
+		if command != "CONNECTED" {
+			key, value = stompHeaderUnescaper.Replace(key), stompHeaderUnescaper.Replace(value)
+		}
+		if _, ok := frame.headers[key]; !ok {
+			frame.headers[key] = value
+		}
+	}
+
+	if val, ok := frame.headers["content-length"]; ok {
+		length, err := strconv.ParseInt(val, 10, 64)
+		if err != nil || length < 0 {
+			return nil, fmt.Errorf("invalid STOMP content-length %q", val)
+		}
+		kept := length
+		if kept > stompMaxBodySize {
+			kept = stompMaxBodySize
+		}
+		frame.body = make([]byte, kept)
+		if _, err := io.ReadFull(r, frame.body); err != nil {
+			return nil, err
+		}
+		if _, err := io.CopyN(ioutil.Discard, r, length-kept); err != nil {
+			return nil, err
+		}
+		if nul, err := r.ReadByte(); err != nil {
+			return nil, err
+		} else if nul != 0 {
+			return nil, errors.New("STOMP frame body is not NUL terminated")
+		}
+		return frame, nil
+	}
+
+	for {
+		chunk, err := r.ReadSlice(0)
+		if err != nil && err != bufio.ErrBufferFull {
+			return nil, err
+		}
+		if err == nil {
+			chunk = chunk[:len(chunk)-1]
+		}
+		if room := stompMaxBodySize - len(frame.body); room > 0 {
+			if len(chunk) > room {
+				chunk = chunk[:room]
+			}
+			frame.body = append(frame.body, chunk...)
+		}
+		if err == nil {
+			return frame, nil
+		}
+	}
+}
+
+// readStompLine reads a line without its EOL, failing once the lines of the frame exceed the remaining budget
+func readStompLine(r *bufio.Reader, budget *int) (string, error) {
+	var line []byte
+	for {
+		chunk, err := r.ReadSlice('\n')
+		*budget -= len(chunk)
+		if *budget < 0 {
+			return "", fmt.Errorf("STOMP frame headers larger than %d bytes", stompMaxHeaderSize)
+		}
+		line = append(line, chunk...)
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(line), "\r\n"), nil
+	}
+}
+
+// stompFrameError turns an unexpected frame, usually an ERROR, into an error
+func stompFrameError(frame *stompFrame) error {
+	if frame.command == "ERROR" {
+		return fmt.Errorf("STOMP error from the ActiveMQ broker: %s", frame.headers["message"])
+	}
+	return fmt.Errorf("unexpected STOMP frame %s from the ActiveMQ broker", frame.command)
+}
@@ -0,0 +1,140 @@
+package scalers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestReadStompFrame(t *testing.T) {
+	frames := "\n\nMESSAGE\ndestination:/queue/a\\cb\nbrowser:end\nbrowser:ignored\n\nbody\x00\n" +
+		"MESSAGE\ncontent-length:5\n\na\x00b\nc\x00" +
+		"CONNECTED\nserver:ActiveMQ/5\\c17\n\n\x00"
+	reader := bufio.NewReader(strings.NewReader(frames))
+
+	frame, err := readStompFrame(reader)
+	if err != nil {
+		t.Fatal("Could not read frame:", err)
+	}
+	if frame.command != "MESSAGE" || frame.headers["destination"] != "/queue/a:b" || frame.headers["browser"] != "end" || string(frame.body) != "body" {
+		t.Errorf("Wrong frame: %+v", frame)
+	}
+
+	frame, err = readStompFrame(reader)
+	if err != nil {
+		t.Fatal("Could not read frame:", err)
+	}
+	if string(frame.body) != "a\x00b\nc" {
+		t.Errorf("Wrong body: %q", frame.body)
+	}
+
+	// CONNECTED frames are not escaped
+	frame, err = readStompFrame(reader)
+	if err != nil {
+		t.Fatal("Could not read frame:", err)
+	}
+	if frame.headers["server"] != `ActiveMQ/5\c17` {
+		t.Errorf("Wrong server header: %s", frame.headers["server"])
+	}
+}
+
+func TestReadStompFrameLimits(t *testing.T) {
+	// only stompMaxBodySize bytes of a body are kept, whatever its content-length
+	body := strings.Repeat("x", stompMaxBodySize+10)
+	frames := fmt.Sprintf("MESSAGE\ncontent-length:%d\n\n%s\x00", len(body), body) +
+		"MESSAGE\n\n" + body + "\x00"
+	reader := bufio.NewReader(strings.NewReader(frames))
+	for i := 0; i < 2; i++ {
+		frame, err := readStompFrame(reader)
+		if err != nil {
+			t.Fatal("Could not read frame:", err)
+		}
+		if len(frame.body) != stompMaxBodySize {
+			t.Errorf("Wrong body size: %d, expected: %d", len(frame.body), stompMaxBodySize)
+		}
+	}
+
+	// a content-length beyond what is sent is not allocated
+	if _, err := readStompFrame(bufio.NewReader(strings.NewReader("MESSAGE\ncontent-length:9000000000000\n\nbody\x00"))); err == nil {
+		t.Error("Expected error but got success")
+	}
+
+	// nor are endless headers
+	headers := "MESSAGE\n" + strings.Repeat("key:value\n", stompMaxHeaderSize/10+1) + "\n\x00"
+	if _, err := readStompFrame(bufio.NewReader(strings.NewReader(headers))); err == nil {
+		t.Error("Expected error but got success")
+	}
+}
+
+func TestWriteStompFrame(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeStompFrame(&buf, "SUBSCRIBE", "destination", "/queue/a:b", "id", "0"); err != nil {
+		t.Fatal("Could not write frame:", err)
+	}
+	if expected := "SUBSCRIBE\ndestination:/queue/a\\cb\nid:0\n\n\x00"; buf.String() != expected {
+		t.Errorf("Wrong frame: %q, expected: %q", buf.String(), expected)
+	}
+}
+
+// serveStomp runs a fake ActiveMQ STOMP endpoint holding queueSize messages, or rejecting every login if queueSize is negative
+func serveStomp(t *testing.T, queueSize int) net.Listener {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("Could not listen:", err)
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				connect, err := readStompFrame(reader)
+				if err != nil || connect.command != "CONNECT" {
+					return
+				}
+				if queueSize < 0 || connect.headers["login"] != "testUsername" || connect.headers["passcode"] != "pass123" {
+					_ = writeStompFrame(conn, "ERROR", "message", "User name [testUsername] or password is invalid.")
+					return
+				}
+				_ = writeStompFrame(conn, "CONNECTED", "version", "1.2")
+				subscribe, err := readStompFrame(reader)
+				if err != nil || subscribe.command != "SUBSCRIBE" || subscribe.headers["browser"] != "true" || subscribe.headers["destination"] != "/queue/testQueue" {
+					_ = writeStompFrame(conn, "ERROR", "message", "unexpected frame")
+					return
+				}
+				for i := 0; i < queueSize; i++ {
+					_ = writeStompFrame(conn, "MESSAGE", "subscription", "0", "destination", "/queue/testQueue")
+				}
+				_ = writeStompFrame(conn, "MESSAGE", "subscription", "0", "destination", "/queue/testQueue", "browser", "end")
+				_, _ = readStompFrame(reader)
+			}()
+		}
+	}()
+	return listener
+}
+
+func TestActiveMQStompBrowseQueue(t *testing.T) {
+	listener := serveStomp(t, 3)
+	defer listener.Close()
+
+	client, err := dialActiveMQStomp(context.Background(), listener.Addr().String(), nil, "testUsername", "pass123")
+	if err != nil {
+		t.Fatal("Could not connect:", err)
+	}
+	defer client.Close()
+
+	count, err := client.browseQueue("testQueue")
+	if err != nil {
+		t.Fatal("Could not browse queue:", err)
+	}
+	if count != 3 {
+		t.Errorf("Wrong count: %d, expected: 3", count)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -156,3 +157,50 @@ func CreateHTTPRoundTripper(roundTripperType TransportType, auth *AuthMeta, conf
 
 	return rt, nil
 }
+
+// ParseTLSConfig builds the client TLS config from the tls, cert, key, ca and unsafeSsl parameters.
+// It returns nil when none of them is set. unsafeSsl is rejected unless allowInsecure is set.
+func ParseTLSConfig(authParams map[string]string, allowInsecure bool) (*tls.Config, error) {
+	enableTLS := false
+	switch val := strings.TrimSpace(authParams["tls"]); val {
+	case "enable":
+		if authParams["cert"] == "" || authParams["key"] == "" {
+			return nil, errors.New("both cert and key must be provided when tls is enabled")
+		}
+		enableTLS = true
+	case "", "disable":
+	default:
+		return nil, fmt.Errorf("err incorrect value for TLS given: %s", val)
+	}
+
+	unsafeSsl := false
+	if val, ok := authParams["unsafeSsl"]; ok && val != "" {
+		var err error
+		if unsafeSsl, err = strconv.ParseBool(val); err != nil {
+			return nil, fmt.Errorf("error parsing unsafeSsl: %s", err)
+		}
+		if unsafeSsl && !allowInsecure {
+			return nil, errors.New("unsafeSsl is not allowed")
+		}
+	}
+
+	ca := authParams["ca"]
+	if !enableTLS && ca == "" && !unsafeSsl {
+		return nil, nil
+	}
+
+	config := &tls.Config{}
+	if enableTLS || ca != "" {
+		cert, key := "", ""
+		if enableTLS {
+			cert, key = authParams["cert"], authParams["key"]
+		}
+		var err error
+		if config, err = kedautil.NewTLSConfig(cert, key, ca); err != nil {
+			return nil, fmt.Errorf("error creating the TLS config: %s", err)
+		}
+	}
+	// NewTLSConfig skips verification whenever a CA is given, verify against the CA unless unsafeSsl is set
+	config.InsecureSkipVerify = unsafeSsl
+	return config, nil
+}
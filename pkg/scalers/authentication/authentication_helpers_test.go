@@ -0,0 +1,99 @@
+package authentication
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// generateTestCertificate returns a PEM encoded self-signed certificate and its key
+func generateTestCertificate(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "keda"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}))
+}
+
+func TestParseTLSConfig(t *testing.T) {
+	cert, key := generateTestCertificate(t)
+
+	testCases := []struct {
+		name          string
+		authParams    map[string]string
+		allowInsecure bool
+		isNil         bool
+		certificates  int
+		rootCAs       bool
+		skipVerify    bool
+		isError       bool
+	}{
+		{name: "nothing set", authParams: map[string]string{}, isNil: true},
+		{name: "tls disabled", authParams: map[string]string{"tls": "disable", "cert": cert, "key": key}, isNil: true},
+		{name: "client certificate", authParams: map[string]string{"tls": "enable", "cert": cert, "key": key}, certificates: 1},
+		{name: "client certificate and ca", authParams: map[string]string{"tls": "enable", "cert": cert, "key": key, "ca": cert}, certificates: 1, rootCAs: true},
+		{name: "ca only", authParams: map[string]string{"ca": cert}, rootCAs: true},
+		{name: "unsafeSsl", authParams: map[string]string{"unsafeSsl": "true"}, allowInsecure: true, skipVerify: true},
+		{name: "unsafeSsl with ca", authParams: map[string]string{"ca": cert, "unsafeSsl": "true"}, allowInsecure: true, rootCAs: true, skipVerify: true},
+		{name: "unsafeSsl not allowed", authParams: map[string]string{"unsafeSsl": "true"}, isError: true},
+		{name: "invalid unsafeSsl", authParams: map[string]string{"unsafeSsl": "maybe"}, allowInsecure: true, isError: true},
+		{name: "invalid tls", authParams: map[string]string{"tls": "yes", "cert": cert, "key": key}, isError: true},
+		{name: "cert without key", authParams: map[string]string{"tls": "enable", "cert": cert}, isError: true},
+		{name: "key without cert", authParams: map[string]string{"tls": "enable", "key": key}, isError: true},
+		{name: "invalid key pair", authParams: map[string]string{"tls": "enable", "cert": cert, "key": "invalid"}, isError: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			config, err := ParseTLSConfig(testCase.authParams, testCase.allowInsecure)
+			if testCase.isError {
+				if err == nil {
+					t.Error("Expected error but got success")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("Expected success but got error", err)
+			}
+			if testCase.isNil {
+				if config != nil {
+					t.Error("Expected no TLS config")
+				}
+				return
+			}
+			if config == nil {
+				t.Fatal("Expected a TLS config")
+			}
+			if len(config.Certificates) != testCase.certificates {
+				t.Errorf("Wrong number of client certificates: %d, expected: %d", len(config.Certificates), testCase.certificates)
+			}
+			if (config.RootCAs != nil) != testCase.rootCAs {
+				t.Errorf("Wrong root CAs, expected set: %t", testCase.rootCAs)
+			}
+			if config.InsecureSkipVerify != testCase.skipVerify {
+				t.Errorf("Wrong InsecureSkipVerify: %t, expected: %t", config.InsecureSkipVerify, testCase.skipVerify)
+			}
+		})
+	}
+}
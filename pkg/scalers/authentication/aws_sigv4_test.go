@@ -0,0 +1,125 @@
+package authentication
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+)
+
+func TestParseAWSSigV4Config(t *testing.T) {
+	testCases := []struct {
+		name       string
+		authParams map[string]string
+		expected   *AWSSigV4Config
+		isError    bool
+	}{
+		{
+			name:       "static credentials",
+			authParams: map[string]string{"awsAccessKeyID": "AKID", "awsSecretAccessKey": "secret", "awsRegion": "eu-west-1"},
+			expected:   &AWSSigV4Config{AccessKeyID: "AKID", SecretAccessKey: "secret", Region: "eu-west-1", Service: "execute-api"},
+		},
+		{
+			name:       "temporary credentials and service",
+			authParams: map[string]string{"awsAccessKeyId": "ASIA", "awsSecretAccessKey": "secret", "awsSessionToken": "token", "awsRegion": "eu-west-1", "awsService": "es"},
+			expected:   &AWSSigV4Config{AccessKeyID: "ASIA", SecretAccessKey: "secret", SessionToken: "token", Region: "eu-west-1", Service: "es"},
+		},
+		{name: "missing access key", authParams: map[string]string{"awsSecretAccessKey": "secret", "awsRegion": "eu-west-1"}, isError: true},
+		{name: "missing secret", authParams: map[string]string{"awsAccessKeyID": "AKID", "awsRegion": "eu-west-1"}, isError: true},
+		{name: "missing region", authParams: map[string]string{"awsAccessKeyID": "AKID", "awsSecretAccessKey": "secret"}, isError: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			config, err := ParseAWSSigV4Config(testCase.authParams)
+			if testCase.isError {
+				if err == nil {
+					t.Error("Expected error but got success")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("Expected success but got error", err)
+			}
+			if *config != *testCase.expected {
+				t.Errorf("Wrong config %+v, expected %+v", config, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestAWSSigV4RoundTripper(t *testing.T) {
+	signTime := time.Date(2022, 2, 7, 10, 52, 40, 0, time.UTC)
+	config := &AWSSigV4Config{AccessKeyID: "ASIA", SecretAccessKey: "secret", SessionToken: "token", Region: "eu-west-1", Service: "execute-api"}
+
+	var authorization, securityToken, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ := ioutil.ReadAll(r.Body)
+		authorization = r.Header.Get("Authorization")
+		securityToken = r.Header.Get("X-Amz-Security-Token")
+		body = string(received)
+
+		// sign the received request again with the same credentials and time, only keeping the headers
+		// that were signed, as the transport adds some after signing
+		expected, _ := http.NewRequest(r.Method, "http://"+r.Host+r.URL.RequestURI(), bytes.NewReader(received))
+		signedHeaders := authorization[strings.Index(authorization, "SignedHeaders=")+len("SignedHeaders=") : strings.Index(authorization, ", Signature=")]
+		for _, header := range strings.Split(signedHeaders, ";") {
+			if header != "host" {
+				expected.Header.Set(header, r.Header.Get(header))
+			}
+		}
+		signer := v4.NewSigner(credentials.NewStaticCredentials(config.AccessKeyID, config.SecretAccessKey, config.SessionToken))
+		if _, err := signer.Sign(expected, bytes.NewReader(received), config.Service, config.Region, signTime); err != nil {
+			t.Error("Could not sign the request:", err)
+		}
+		if expected.Header.Get("Authorization") != authorization {
+			t.Errorf("Wrong signature %s, expected %s", authorization, expected.Header.Get("Authorization"))
+		}
+	}))
+	defer server.Close()
+
+	roundTripper := NewAWSSigV4RoundTripper(config, nil).(*awsSigV4RoundTripper)
+	roundTripper.now = func() time.Time { return signTime }
+	client := &http.Client{Transport: roundTripper}
+
+	testCases := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{"GET", "/api/jolokia/read/org.apache.activemq:type=Broker,brokerName=localhost/QueueSize", ""},
+		{"POST", "/api/jolokia/", `{"type":"read"}`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.method, func(t *testing.T) {
+			req, err := http.NewRequest(testCase.method, server.URL+testCase.path, strings.NewReader(testCase.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatal("Expected success but got error", err)
+			}
+			resp.Body.Close()
+
+			if !strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=ASIA/20220207/eu-west-1/execute-api/aws4_request") {
+				t.Errorf("Wrong Authorization header %s", authorization)
+			}
+			if securityToken != "token" {
+				t.Errorf("Wrong X-Amz-Security-Token header %s", securityToken)
+			}
+			if body != testCase.body {
+				t.Errorf("Wrong body %q, expected %q", body, testCase.body)
+			}
+		})
+	}
+}
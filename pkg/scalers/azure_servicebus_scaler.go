@@ -47,6 +47,12 @@ const (
 	defaultTargetMessageCount            = 5
 )
 
+const (
+	activeMessagesCountType     = "activeMessages"
+	deadLetterMessagesCountType = "deadLetterMessages"
+	transferMessagesCountType   = "transferMessages"
+)
+
 var azureServiceBusLog = logf.Log.WithName("azure_servicebus_scaler")
 
 type azureServiceBusScaler struct {
@@ -65,6 +71,7 @@ type azureServiceBusMetadata struct {
 	entityType       entityType
 	namespace        string
 	endpointSuffix   string
+	messageCountType string
 	scalerIndex      int
 }
 
@@ -99,6 +106,16 @@ func parseAzureServiceBusMetadata(config *ScalerConfig) (*azureServiceBusMetadat
 		}
 	}
 
+	meta.messageCountType = activeMessagesCountType
+	if val, ok := config.TriggerMetadata["messageCountType"]; ok && val != "" {
+		switch val {
+		case activeMessagesCountType, deadLetterMessagesCountType, transferMessagesCountType:
+			meta.messageCountType = val
+		default:
+			return nil, fmt.Errorf("invalid messageCountType %q, must be one of %s, %s or %s", val, activeMessagesCountType, deadLetterMessagesCountType, transferMessagesCountType)
+		}
+	}
+
 	// get queue name OR topic and subscription name & set entity type accordingly
 	if val, ok := config.TriggerMetadata["queueName"]; ok {
 		meta.queueName = val
@@ -190,6 +207,14 @@ func (s *azureServiceBusScaler) GetMetricSpecForScaling(context.Context) []v2bet
 		metricName = s.metadata.topicName
 	}
 
+	// keep the original metric name for active messages so existing HPAs are unaffected
+	switch s.metadata.messageCountType {
+	case deadLetterMessagesCountType:
+		metricName = fmt.Sprintf("%s-deadletter", metricName)
+	case transferMessagesCountType:
+		metricName = fmt.Sprintf("%s-transfer", metricName)
+	}
+
 	externalMetric := &v2beta2.ExternalMetricSource{
 		Metric: v2beta2.MetricIdentifier{
 			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(fmt.Sprintf("azure-servicebus-%s", metricName))),
@@ -252,9 +277,9 @@ func (s *azureServiceBusScaler) GetAzureServiceBusLength(ctx context.Context) (i
 	// switch case for queue vs topic here
 	switch s.metadata.entityType {
 	case queue:
-		return getQueueEntityFromNamespace(ctx, namespace, s.metadata.queueName)
+		return getQueueEntityFromNamespace(ctx, namespace, s.metadata.queueName, s.metadata.messageCountType)
 	case subscription:
-		return getSubscriptionEntityFromNamespace(ctx, namespace, s.metadata.topicName, s.metadata.subscriptionName)
+		return getSubscriptionEntityFromNamespace(ctx, namespace, s.metadata.topicName, s.metadata.subscriptionName, s.metadata.messageCountType)
 	default:
 		return -1, fmt.Errorf("no entity type")
 	}
@@ -286,7 +311,7 @@ func (s *azureServiceBusScaler) getServiceBusNamespace(ctx context.Context) (*se
 	return namespace, nil
 }
 
-func getQueueEntityFromNamespace(ctx context.Context, ns *servicebus.Namespace, queueName string) (int32, error) {
+func getQueueEntityFromNamespace(ctx context.Context, ns *servicebus.Namespace, queueName, messageCountType string) (int32, error) {
 	// get queue manager from namespace
 	queueManager := ns.NewQueueManager()
 
@@ -296,10 +321,10 @@ func getQueueEntityFromNamespace(ctx context.Context, ns *servicebus.Namespace,
 		return -1, err
 	}
 
-	return *queueEntity.CountDetails.ActiveMessageCount, nil
+	return getMessageCountFromDetails(queueEntity.CountDetails, messageCountType)
 }
 
-func getSubscriptionEntityFromNamespace(ctx context.Context, ns *servicebus.Namespace, topicName, subscriptionName string) (int32, error) {
+func getSubscriptionEntityFromNamespace(ctx context.Context, ns *servicebus.Namespace, topicName, subscriptionName, messageCountType string) (int32, error) {
 	// get subscription manager from namespace
 	subscriptionManager, err := ns.NewSubscriptionManager(topicName)
 	if err != nil {
@@ -312,5 +337,27 @@ func getSubscriptionEntityFromNamespace(ctx context.Context, ns *servicebus.Name
 		return -1, err
 	}
 
-	return *subscriptionEntity.CountDetails.ActiveMessageCount, nil
+	return getMessageCountFromDetails(subscriptionEntity.CountDetails, messageCountType)
+}
+
+// Returns the message count selected by messageCountType from the entity's runtime properties
+func getMessageCountFromDetails(details *servicebus.CountDetails, messageCountType string) (int32, error) {
+	if details == nil {
+		return -1, fmt.Errorf("no count details returned for service bus entity")
+	}
+
+	var count *int32
+	switch messageCountType {
+	case deadLetterMessagesCountType:
+		count = details.DeadLetterMessageCount
+	case transferMessagesCountType:
+		count = details.TransferMessageCount
+	default:
+		count = details.ActiveMessageCount
+	}
+
+	if count == nil {
+		return 0, nil
+	}
+	return *count, nil
 }
@@ -23,6 +23,8 @@ import (
 	"testing"
 	"time"
 
+	servicebus "github.com/Azure/azure-service-bus-go"
+
 	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
 )
 
@@ -97,11 +99,19 @@ var parseServiceBusMetadataDataset = []parseServiceBusMetadataTestData{
 	{map[string]string{"queueName": queueName}, true, queue, "", map[string]string{}, kedav1alpha1.PodIdentityProviderAzure},
 	// correct pod identity
 	{map[string]string{"queueName": queueName, "namespace": namespaceName}, false, queue, defaultSuffix, map[string]string{}, kedav1alpha1.PodIdentityProviderAzure},
+	// queue with dead-letter message count type
+	{map[string]string{"queueName": queueName, "connectionFromEnv": connectionSetting, "messageCountType": "deadLetterMessages"}, false, queue, defaultSuffix, map[string]string{}, ""},
+	// subscription with transfer message count type
+	{map[string]string{"topicName": topicName, "subscriptionName": subscriptionName, "connectionFromEnv": connectionSetting, "messageCountType": "transferMessages"}, false, subscription, defaultSuffix, map[string]string{}, ""},
+	// invalid message count type
+	{map[string]string{"queueName": queueName, "connectionFromEnv": connectionSetting, "messageCountType": "scheduledMessages"}, true, none, "", map[string]string{}, ""},
 }
 
 var azServiceBusMetricIdentifiers = []azServiceBusMetricIdentifier{
 	{&parseServiceBusMetadataDataset[1], 0, "s0-azure-servicebus-testqueue"},
 	{&parseServiceBusMetadataDataset[3], 1, "s1-azure-servicebus-testtopic"},
+	{&parseServiceBusMetadataDataset[18], 0, "s0-azure-servicebus-testqueue-deadletter"},
+	{&parseServiceBusMetadataDataset[19], 1, "s1-azure-servicebus-testtopic-transfer"},
 }
 
 var commonHTTPClient = &http.Client{
@@ -156,6 +166,38 @@ func TestParseServiceBusMetadata(t *testing.T) {
 	}
 }
 
+func TestGetMessageCountFromDetails(t *testing.T) {
+	active, deadLetter, transfer := int32(3), int32(7), int32(11)
+	details := &servicebus.CountDetails{
+		ActiveMessageCount:     &active,
+		DeadLetterMessageCount: &deadLetter,
+		TransferMessageCount:   &transfer,
+	}
+
+	for countType, expected := range map[string]int32{
+		activeMessagesCountType:     3,
+		deadLetterMessagesCountType: 7,
+		transferMessagesCountType:   11,
+	} {
+		count, err := getMessageCountFromDetails(details, countType)
+		if err != nil {
+			t.Errorf("Expected success for %s but got error: %s", countType, err)
+		}
+		if count != expected {
+			t.Errorf("Expected %d messages for %s, got %d", expected, countType, count)
+		}
+	}
+
+	count, err := getMessageCountFromDetails(&servicebus.CountDetails{ActiveMessageCount: &active}, deadLetterMessagesCountType)
+	if err != nil || count != 0 {
+		t.Errorf("Expected 0 messages for missing dead-letter count, got %d (%v)", count, err)
+	}
+
+	if _, err := getMessageCountFromDetails(nil, activeMessagesCountType); err == nil {
+		t.Error("Expected error for missing count details but got success")
+	}
+}
+
 func TestGetServiceBusLength(t *testing.T) {
 	t.Log("This test will use the environment variable SERVICEBUS_CONNECTION_STRING if it is set")
 	t.Log("If set, it will connect to the servicebus namespace specified by the connection string & check:")
@@ -2,6 +2,7 @@ package scalers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -30,6 +31,18 @@ const (
 	global
 )
 
+const (
+	datadogEmptySeriesError = "error"
+	datadogEmptySeriesZero  = "zero"
+	defaultQueryAggregator  = "avg"
+)
+
+// datadogQueryAggregators are the rollup methods supported by Datadog for the queryAggregator
+var datadogQueryAggregators = []string{"avg", "sum", "min", "max", "count"}
+
+// errDatadogNoData is returned when the query returns no series or no point with a value
+var errDatadogNoData = errors.New("no Datadog metrics returned")
+
 type datadogMetadata struct {
 	apiKey      string
 	appKey      string
@@ -39,6 +52,8 @@ type datadogMetadata struct {
 	vType       valueType
 	metricName  string
 	age         int
+	// emptySeries is either datadogEmptySeriesError or datadogEmptySeriesZero
+	emptySeries string
 }
 
 var datadogLog = logf.Log.WithName("datadog_scaler")
@@ -92,10 +107,33 @@ func parseDatadogMetadata(config *ScalerConfig) (*datadogMetadata, error) {
 		meta.age = 90 // Default window 90 seconds
 	}
 
+	aggregator := defaultQueryAggregator
+	if val, ok := config.TriggerMetadata["queryAggregator"]; ok && val != "" {
+		aggregator = strings.ToLower(val)
+		if !isDatadogQueryAggregator(aggregator) {
+			return nil, fmt.Errorf("queryAggregator has to be one of %s", strings.Join(datadogQueryAggregators, ", "))
+		}
+		if strings.Contains(meta.query, ".rollup(") {
+			return nil, fmt.Errorf("queryAggregator can not be used with a query that already has a rollup")
+		}
+	}
+
 	// For all the points in a given window, we take the rollup to the window size
-	rollup := fmt.Sprintf(".rollup(avg, %d)", meta.age)
+	rollup := fmt.Sprintf(".rollup(%s, %d)", aggregator, meta.age)
 	meta.query += rollup
 
+	meta.emptySeries = datadogEmptySeriesError
+	if val, ok := config.TriggerMetadata["emptySeries"]; ok && val != "" {
+		switch strings.ToLower(val) {
+		case datadogEmptySeriesError:
+			meta.emptySeries = datadogEmptySeriesError
+		case datadogEmptySeriesZero:
+			meta.emptySeries = datadogEmptySeriesZero
+		default:
+			return nil, fmt.Errorf("emptySeries has to be %s or %s", datadogEmptySeriesError, datadogEmptySeriesZero)
+		}
+	}
+
 	if val, ok := config.TriggerMetadata["type"]; ok {
 		val = strings.ToLower(val)
 		switch val {
@@ -136,6 +174,15 @@ func parseDatadogMetadata(config *ScalerConfig) (*datadogMetadata, error) {
 	return &meta, nil
 }
 
+func isDatadogQueryAggregator(aggregator string) bool {
+	for _, supported := range datadogQueryAggregators {
+		if aggregator == supported {
+			return true
+		}
+	}
+	return false
+}
+
 // newDatddogConnection tests a connection to the Datadog API
 func newDatadogConnection(ctx context.Context, meta *datadogMetadata, config *ScalerConfig) (*datadog.APIClient, error) {
 	ctx = context.WithValue(
@@ -176,48 +223,33 @@ func (s *datadogScaler) Close(context.Context) error {
 
 // IsActive returns true if we are able to get metrics from Datadog
 func (s *datadogScaler) IsActive(ctx context.Context) (bool, error) {
-	ctx = context.WithValue(
-		ctx,
-		datadog.ContextAPIKeys,
-		map[string]datadog.APIKey{
-			"apiKeyAuth": {
-				Key: s.metadata.apiKey,
-			},
-			"appKeyAuth": {
-				Key: s.metadata.appKey,
-			},
-		},
-	)
-
-	ctx = context.WithValue(ctx,
-		datadog.ContextServerVariables,
-		map[string]string{
-			"site": s.metadata.datadogSite,
-		})
-
-	resp, _, err := s.apiClient.MetricsApi.QueryMetrics(ctx, time.Now().Unix()-int64(s.metadata.age), time.Now().Unix(), s.metadata.query)
-
+	_, err := s.queryDatadog(ctx)
+	if errors.Is(err, errDatadogNoData) {
+		return false, nil
+	}
 	if err != nil {
 		return false, err
 	}
 
-	series := resp.GetSeries()
+	return true, nil
+}
 
-	if len(series) == 0 {
-		return false, nil
+// getQueryResult returns result of the scaler query, an empty series is 0 when emptySeries is zero
+func (s *datadogScaler) getQueryResult(ctx context.Context) (int, error) {
+	value, err := s.queryDatadog(ctx)
+	if errors.Is(err, errDatadogNoData) && s.metadata.emptySeries == datadogEmptySeriesZero {
+		return 0, nil
 	}
-
-	points := series[0].GetPointlist()
-
-	if len(points) == 0 {
-		return false, nil
+	if err != nil {
+		return -1, err
 	}
 
-	return true, nil
+	return int(value), nil
 }
 
-// getQueryResult returns result of the scaler query
-func (s *datadogScaler) getQueryResult(ctx context.Context) (int, error) {
+// queryDatadog runs the query and returns the most recent point with a value of the first series,
+// errDatadogNoData if there is none
+func (s *datadogScaler) queryDatadog(ctx context.Context) (float64, error) {
 	ctx = context.WithValue(
 		ctx,
 		datadog.ContextAPIKeys,
@@ -245,16 +277,18 @@ func (s *datadogScaler) getQueryResult(ctx context.Context) (int, error) {
 	series := resp.GetSeries()
 
 	if len(series) == 0 {
-		return 0, fmt.Errorf("no Datadog metrics returned")
+		return 0, errDatadogNoData
 	}
 
+	// points are [timestamp, value] pairs in ascending order, the value is null for gaps in the data
 	points := series[0].GetPointlist()
-
-	if len(points) == 0 || len(points[0]) < 2 {
-		return 0, fmt.Errorf("no Datadog metrics returned")
+	for i := len(points) - 1; i >= 0; i-- {
+		if len(points[i]) >= 2 && points[i][1] != nil {
+			return *points[i][1], nil
+		}
 	}
 
-	return int(*points[0][1]), nil
+	return 0, errDatadogNoData
 }
 
 // GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
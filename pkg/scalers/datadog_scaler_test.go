@@ -2,7 +2,11 @@ package scalers
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+
+	datadog "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
 )
 
 type datadogMetricIdentifier struct {
@@ -43,6 +47,16 @@ var testDatadogMetadata = []datadogAuthMetadataTestData{
 	{map[string]string{"query": "sum:trace.redis.command.hits{env:none,service:redis}.as_count()", "queryValue": "7"}, map[string]string{"apiKey": "apiKey"}, true},
 	// invalid query missing {
 	{map[string]string{"query": "sum:trace.redis.command.hits.as_count()", "queryValue": "7"}, map[string]string{}, true},
+	// queryAggregator
+	{map[string]string{"query": "sum:trace.redis.command.hits{env:none,service:redis}.as_count()", "queryValue": "7", "queryAggregator": "max"}, map[string]string{"apiKey": "apiKey", "appKey": "appKey"}, false},
+	// invalid queryAggregator
+	{map[string]string{"query": "sum:trace.redis.command.hits{env:none,service:redis}.as_count()", "queryValue": "7", "queryAggregator": "median"}, map[string]string{"apiKey": "apiKey", "appKey": "appKey"}, true},
+	// queryAggregator with a rollup in the query
+	{map[string]string{"query": "sum:trace.redis.command.hits{env:none,service:redis}.rollup(sum, 30)", "queryValue": "7", "queryAggregator": "max"}, map[string]string{"apiKey": "apiKey", "appKey": "appKey"}, true},
+	// emptySeries
+	{map[string]string{"query": "sum:trace.redis.command.hits{env:none,service:redis}.as_count()", "queryValue": "7", "emptySeries": "zero"}, map[string]string{"apiKey": "apiKey", "appKey": "appKey"}, false},
+	// invalid emptySeries
+	{map[string]string{"query": "sum:trace.redis.command.hits{env:none,service:redis}.as_count()", "queryValue": "7", "emptySeries": "ignore"}, map[string]string{"apiKey": "apiKey", "appKey": "appKey"}, true},
 }
 
 func TestDatadogScalerAuthParams(t *testing.T) {
@@ -81,3 +95,85 @@ func TestDatadogGetMetricSpecForScaling(t *testing.T) {
 		}
 	}
 }
+
+func TestDatadogQueryAggregator(t *testing.T) {
+	testCases := []struct {
+		aggregator string
+		expected   string
+	}{
+		{"", "sum:trace.redis.command.hits{env:none,service:redis}.as_count().rollup(avg, 60)"},
+		{"max", "sum:trace.redis.command.hits{env:none,service:redis}.as_count().rollup(max, 60)"},
+		{"SUM", "sum:trace.redis.command.hits{env:none,service:redis}.as_count().rollup(sum, 60)"},
+	}
+
+	for _, testCase := range testCases {
+		meta, err := parseDatadogMetadata(&ScalerConfig{
+			TriggerMetadata: map[string]string{"query": "sum:trace.redis.command.hits{env:none,service:redis}.as_count()", "queryValue": "7", "age": "60", "queryAggregator": testCase.aggregator},
+			AuthParams:      map[string]string{"apiKey": "apiKey", "appKey": "appKey"},
+		})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		if meta.query != testCase.expected {
+			t.Errorf("Wrong query %s, expected %s", meta.query, testCase.expected)
+		}
+	}
+}
+
+func TestDatadogEmptySeries(t *testing.T) {
+	testCases := []struct {
+		name        string
+		response    string
+		emptySeries string
+		expected    int
+		isActive    bool
+		isError     bool
+	}{
+		{"latest point", `{"series":[{"pointlist":[[1644231100000,3],[1644231160000,5]]}]}`, "", 5, true, false},
+		{"gap at the end", `{"series":[{"pointlist":[[1644231100000,3],[1644231160000,null]]}]}`, "", 3, true, false},
+		{"no series", `{"series":[]}`, "", 0, false, true},
+		{"no points", `{"series":[{"pointlist":[]}]}`, "error", 0, false, true},
+		{"no series as zero", `{"series":[]}`, "zero", 0, false, false},
+		{"only gaps as zero", `{"series":[{"pointlist":[[1644231160000,null]]}]}`, "zero", 0, false, false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(testCase.response))
+			}))
+			defer server.Close()
+
+			meta, err := parseDatadogMetadata(&ScalerConfig{
+				TriggerMetadata: map[string]string{"query": "sum:trace.redis.command.hits{env:none,service:redis}.as_count()", "queryValue": "7", "emptySeries": testCase.emptySeries},
+				AuthParams:      map[string]string{"apiKey": "apiKey", "appKey": "appKey"},
+			})
+			if err != nil {
+				t.Fatal("Could not parse metadata:", err)
+			}
+			configuration := datadog.NewConfiguration()
+			configuration.Servers = datadog.ServerConfigurations{{URL: server.URL}}
+			scaler := datadogScaler{metadata: meta, apiClient: datadog.NewAPIClient(configuration)}
+
+			value, err := scaler.getQueryResult(context.Background())
+			if testCase.isError {
+				if err == nil {
+					t.Error("Expected error but got success")
+				}
+			} else if err != nil {
+				t.Error("Expected success but got error", err)
+			} else if value != testCase.expected {
+				t.Errorf("Wrong value %d, expected %d", value, testCase.expected)
+			}
+
+			isActive, err := scaler.IsActive(context.Background())
+			if err != nil {
+				t.Error("Expected success but got error", err)
+			}
+			if isActive != testCase.isActive {
+				t.Errorf("Wrong activity %t, expected %t", isActive, testCase.isActive)
+			}
+		})
+	}
+}
@@ -37,6 +37,7 @@ type elasticsearchMetadata struct {
 	searchTemplateName string
 	parameters         []string
 	valueLocation      string
+	aggregationName    string
 	targetValue        int
 	metricName         string
 }
@@ -108,9 +109,18 @@ func parseElasticsearchMetadata(config *ScalerConfig) (*elasticsearchMetadata, e
 		meta.parameters = splitAndTrimBySep(val, ";")
 	}
 
-	meta.valueLocation, err = GetFromAuthOrMeta(config, "valueLocation")
-	if err != nil {
-		return nil, err
+	// an aggregation, possibly nested such as last_hour.total, is read from aggregations.<name>.value
+	if val, ok := config.TriggerMetadata["aggregationName"]; ok && val != "" {
+		if _, ok := config.TriggerMetadata["valueLocation"]; ok {
+			return nil, fmt.Errorf("aggregationName and valueLocation can not be set both")
+		}
+		meta.aggregationName = strings.TrimSpace(val)
+		meta.valueLocation = fmt.Sprintf("aggregations.%s.value", meta.aggregationName)
+	} else {
+		meta.valueLocation, err = GetFromAuthOrMeta(config, "valueLocation")
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	targetValue, err := GetFromAuthOrMeta(config, "targetValue")
@@ -192,7 +202,12 @@ func (s *elasticsearchScaler) getQueryResult(ctx context.Context) (int, error) {
 	if err != nil {
 		return 0, err
 	}
-	v, err := getValueFromSearch(b, s.metadata.valueLocation)
+	var v int
+	if s.metadata.aggregationName != "" {
+		v, err = getAggregationValueFromSearch(b, s.metadata.aggregationName)
+	} else {
+		v, err = getValueFromSearch(b, s.metadata.valueLocation)
+	}
 	if err != nil {
 		return 0, err
 	}
@@ -232,6 +247,23 @@ func getValueFromSearch(body []byte, valueLocation string) (int, error) {
 	return int(r.Num), nil
 }
 
+// getAggregationValueFromSearch returns the value of a metrics aggregation. Aggregations such as avg or max
+// have a null value when no document matched, which is reported as 0.
+func getAggregationValueFromSearch(body []byte, aggregationName string) (int, error) {
+	aggregation := gjson.GetBytes(body, fmt.Sprintf("aggregations.%s", aggregationName))
+	if !aggregation.Exists() {
+		return 0, fmt.Errorf("aggregation '%s' not found in the search response", aggregationName)
+	}
+	value := aggregation.Get("value")
+	if !value.Exists() {
+		return 0, fmt.Errorf("aggregation '%s' has no value, only metrics aggregations such as sum or avg can be scaled on", aggregationName)
+	}
+	if value.Type == gjson.Null {
+		return 0, nil
+	}
+	return getValueFromSearch(body, fmt.Sprintf("aggregations.%s.value", aggregationName))
+}
+
 // GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
 func (s *elasticsearchScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
 	targetValue := resource.NewQuantity(int64(s.metadata.targetValue), resource.DecimalSI)
@@ -428,3 +428,50 @@ func TestElasticsearchGetMetricSpecForScaling(t *testing.T) {
 		assert.Equal(t, metricSpec[0].External.Metric.Name, testData.name)
 	}
 }
+
+func TestParseElasticsearchAggregationName(t *testing.T) {
+	metadata := map[string]string{
+		"addresses":          "http://localhost:9200",
+		"index":              "index1",
+		"searchTemplateName": "myAwesomeSearch",
+		"aggregationName":    "last_hour.total",
+		"targetValue":        "12",
+	}
+	meta, err := parseElasticsearchMetadata(&ScalerConfig{TriggerMetadata: metadata, AuthParams: map[string]string{}})
+	assert.NoError(t, err)
+	assert.Equal(t, "last_hour.total", meta.aggregationName)
+	assert.Equal(t, "aggregations.last_hour.total.value", meta.valueLocation)
+
+	metadata["valueLocation"] = "hits.hits[0]._source.value"
+	_, err = parseElasticsearchMetadata(&ScalerConfig{TriggerMetadata: metadata, AuthParams: map[string]string{}})
+	assert.EqualError(t, err, "aggregationName and valueLocation can not be set both")
+}
+
+func TestElasticsearchGetAggregationValue(t *testing.T) {
+	body := []byte(`{"hits":{"total":{"value":42},"hits":[]},"aggregations":{"total":{"value":17.0},"empty":{"value":null},"last_hour":{"doc_count":3,"total":{"value":"8"}},"by_host":{"buckets":[]}}}`)
+
+	tests := []struct {
+		name            string
+		aggregationName string
+		expectedValue   int
+		expectedError   string
+	}{
+		{"sum aggregation", "total", 17, ""},
+		{"nested aggregation", "last_hour.total", 8, ""},
+		{"aggregation without documents", "empty", 0, ""},
+		{"missing aggregation", "unknown", 0, "aggregation 'unknown' not found in the search response"},
+		{"bucket aggregation", "by_host", 0, "aggregation 'by_host' has no value, only metrics aggregations such as sum or avg can be scaled on"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			value, err := getAggregationValueFromSearch(body, tc.aggregationName)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedValue, value)
+		})
+	}
+}
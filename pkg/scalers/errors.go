@@ -0,0 +1,63 @@
+/*
+Copyright 2021 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scalers
+
+import "errors"
+
+// The kinds of scaler errors callers can tell apart with errors.Is, e.g. to back off on an unreachable
+// endpoint but surface a configuration or authentication error to the user right away
+var (
+	// ErrConfig is a scaler configuration error, retrying will not help until the trigger is changed
+	ErrConfig = errors.New("invalid scaler configuration")
+	// ErrAuth is a rejection of the scaler credentials by the scaled service
+	ErrAuth = errors.New("scaler authentication failed")
+	// ErrUnreachable is a failure to reach the scaled service, it may be transient
+	ErrUnreachable = errors.New("scaler endpoint unreachable")
+)
+
+// scalerError classifies an error as one of the kinds above, it keeps the message of the error it wraps
+type scalerError struct {
+	kind error
+	err  error
+}
+
+func (e *scalerError) Error() string {
+	return e.err.Error()
+}
+
+func (e *scalerError) Unwrap() error {
+	return e.err
+}
+
+func (e *scalerError) Is(target error) bool {
+	return target == e.kind
+}
+
+// newConfigError classifies err as an ErrConfig
+func newConfigError(err error) error {
+	return &scalerError{kind: ErrConfig, err: err}
+}
+
+// newAuthError classifies err as an ErrAuth
+func newAuthError(err error) error {
+	return &scalerError{kind: ErrAuth, err: err}
+}
+
+// newUnreachableError classifies err as an ErrUnreachable
+func newUnreachableError(err error) error {
+	return &scalerError{kind: ErrUnreachable, err: err}
+}
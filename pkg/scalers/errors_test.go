@@ -0,0 +1,40 @@
+package scalers
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestScalerErrorKinds(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		kind error
+	}{
+		{"config", newConfigError(errors.New("no query given")), ErrConfig},
+		{"auth", newAuthError(errors.New("status 401")), ErrAuth},
+		{"unreachable", newUnreachableError(errors.New("connection refused")), ErrUnreachable},
+		{"wrapped", fmt.Errorf("error inspecting: %w", newUnreachableError(errors.New("connection refused"))), ErrUnreachable},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			for _, kind := range []error{ErrConfig, ErrAuth, ErrUnreachable} {
+				if errors.Is(testCase.err, kind) != (kind == testCase.kind) {
+					t.Errorf("Wrong errors.Is(%q) for the kind %q", testCase.err, kind)
+				}
+			}
+		})
+	}
+
+	// the classification keeps the message and the chain of the classified error
+	cause := errors.New("connection refused")
+	err := newUnreachableError(cause)
+	if err.Error() != cause.Error() {
+		t.Errorf("Wrong message: %s, expected: %s", err, cause)
+	}
+	if !errors.Is(err, cause) {
+		t.Error("Expected the classified error to wrap its cause")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	url_pkg "net/url"
 	"strconv"
+	"strings"
 
 	v2beta2 "k8s.io/api/autoscaling/v2beta2"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -23,8 +24,16 @@ const (
 	grapServerAddress = "serverAddress"
 	grapMetricName    = "metricName"
 	grapQuery         = "query"
+	grapAdditional    = "additionalQueries"
 	grapThreshold     = "threshold"
 	grapQueryTime     = "queryTime"
+	grapAggregation   = "aggregation"
+)
+
+const (
+	grapSumAggregation = "sum"
+	grapAvgAggregation = "avg"
+	grapMaxAggregation = "max"
 )
 
 type graphiteScaler struct {
@@ -36,8 +45,11 @@ type graphiteMetadata struct {
 	serverAddress string
 	metricName    string
 	query         string
-	threshold     int
-	from          string
+	// queries holds the query followed by each line of additionalQueries
+	queries     []string
+	aggregation string
+	threshold   int
+	from        string
 
 	// basic auth
 	enableBasicAuth bool
@@ -84,6 +96,29 @@ func parseGraphiteMetadata(config *ScalerConfig) (*graphiteMetadata, error) {
 		return nil, fmt.Errorf("no %s given", grapQuery)
 	}
 
+	// the targets are separated by lines as ';' and ',' are part of the Graphite target syntax, e.g. tagged series
+	meta.queries = []string{meta.query}
+	if val, ok := config.TriggerMetadata[grapAdditional]; ok && val != "" {
+		for _, target := range strings.Split(strings.TrimSpace(val), "\n") {
+			target = strings.TrimSpace(target)
+			if target == "" {
+				return nil, fmt.Errorf("%s contains an empty target", grapAdditional)
+			}
+			meta.queries = append(meta.queries, target)
+		}
+	}
+
+	if val, ok := config.TriggerMetadata[grapAggregation]; ok && val != "" {
+		switch val {
+		case grapSumAggregation, grapAvgAggregation, grapMaxAggregation:
+			meta.aggregation = val
+		default:
+			return nil, fmt.Errorf("invalid %s %q - must be one of sum, avg or max", grapAggregation, val)
+		}
+	} else if len(meta.queries) > 1 {
+		meta.aggregation = grapSumAggregation
+	}
+
 	if val, ok := config.TriggerMetadata[grapMetricName]; ok && val != "" {
 		meta.metricName = val
 	} else {
@@ -161,8 +196,15 @@ func (s *graphiteScaler) GetMetricSpecForScaling(context.Context) []v2beta2.Metr
 }
 
 func (s *graphiteScaler) ExecuteGrapQuery(ctx context.Context) (float64, error) {
-	queryEscaped := url_pkg.QueryEscape(s.metadata.query)
-	url := fmt.Sprintf("%s/render?from=%s&target=%s&format=json", s.metadata.serverAddress, s.metadata.from, queryEscaped)
+	queries := s.metadata.queries
+	if len(queries) == 0 {
+		queries = []string{s.metadata.query}
+	}
+	params := url_pkg.Values{}
+	params.Set("from", s.metadata.from)
+	params["target"] = queries
+	params.Set("format", "json")
+	url := fmt.Sprintf("%s/render?%s", s.metadata.serverAddress, params.Encode())
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return -1, err
@@ -189,19 +231,42 @@ func (s *graphiteScaler) ExecuteGrapQuery(ctx context.Context) (float64, error)
 
 	if len(result) == 0 {
 		return 0, nil
-	} else if len(result) > 1 {
+	} else if len(result) > 1 && s.metadata.aggregation == "" {
 		return -1, fmt.Errorf("graphite query %s returned multiple series", s.metadata.query)
 	}
 
-	// https://graphite-api.readthedocs.io/en/latest/api.html#json
-	if len(result[0].Datapoints) == 0 {
-		return 0, nil
-	}
+	return aggregateGrapResult(result, s.metadata.aggregation), nil
+}
 
-	latestDatapoint := len(result[0].Datapoints) - 1
-	datapoint := result[0].Datapoints[latestDatapoint][0]
+// aggregateGrapResult combines the latest datapoint of every series with the given
+// aggregation. Series without datapoints are ignored.
+func aggregateGrapResult(result grapQueryResult, aggregation string) float64 {
+	var value float64
+	count := 0
+	for _, series := range result {
+		// https://graphite-api.readthedocs.io/en/latest/api.html#json
+		if len(series.Datapoints) == 0 || len(series.Datapoints[len(series.Datapoints)-1]) == 0 {
+			continue
+		}
+		datapoint := series.Datapoints[len(series.Datapoints)-1][0]
+
+		switch {
+		case count == 0:
+			value = datapoint
+		case aggregation == grapMaxAggregation:
+			if datapoint > value {
+				value = datapoint
+			}
+		default:
+			value += datapoint
+		}
+		count++
+	}
 
-	return datapoint, nil
+	if aggregation == grapAvgAggregation && count > 0 {
+		value /= float64(count)
+	}
+	return value
 }
 
 func (s *graphiteScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
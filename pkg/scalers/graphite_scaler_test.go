@@ -2,6 +2,9 @@ package scalers
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 )
@@ -31,6 +34,14 @@ var testGrapMetadata = []parseGraphiteMetadataTestData{
 	{map[string]string{"serverAddress": "http://localhost:81", "metricName": "request-count", "threshold": "100", "query": "", "queryTime": "-30Seconds", "disableScaleToZero": "true"}, true},
 	// missing queryTime
 	{map[string]string{"serverAddress": "http://localhost:81", "metricName": "request-count", "threshold": "100", "query": "stats.counters.http.hello-world.request.count.count", "queryTime": ""}, true},
+	// multiple targets with aggregation
+	{map[string]string{"serverAddress": "http://localhost:81", "metricName": "request-count", "threshold": "100", "query": "sumSeries(stats.counters.a.count,stats.counters.c.count)", "additionalQueries": "stats.counters.b.count", "queryTime": "-30Seconds", "aggregation": "max"}, false},
+	// multiple targets without aggregation
+	{map[string]string{"serverAddress": "http://localhost:81", "metricName": "request-count", "threshold": "100", "query": "stats.counters.a.count", "additionalQueries": "stats.counters.b.count\nstats.counters.c.count\n", "queryTime": "-30Seconds"}, false},
+	// empty target
+	{map[string]string{"serverAddress": "http://localhost:81", "metricName": "request-count", "threshold": "100", "query": "stats.counters.a.count", "additionalQueries": "stats.counters.b.count\n\nstats.counters.c.count", "queryTime": "-30Seconds"}, true},
+	// invalid aggregation
+	{map[string]string{"serverAddress": "http://localhost:81", "metricName": "request-count", "threshold": "100", "query": "stats.counters.a.count", "queryTime": "-30Seconds", "aggregation": "median"}, true},
 }
 
 var graphiteMetricIdentifiers = []graphiteMetricIdentifier{
@@ -102,3 +113,62 @@ func TestGraphiteScalerAuthParams(t *testing.T) {
 		}
 	}
 }
+
+func TestGraphiteAggregation(t *testing.T) {
+	var targets []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		targets = r.URL.Query()["target"]
+		var series []string
+		for i, target := range targets {
+			series = append(series, fmt.Sprintf(`{"target":%q,"datapoints":[[1,100],[%d,160]]}`, target, (i+1)*10))
+		}
+		series = append(series, `{"target":"empty","datapoints":[]}`)
+		fmt.Fprintf(w, "[%s]", strings.Join(series, ","))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		query       string
+		additional  string
+		aggregation string
+		expected    float64
+		isError     bool
+	}{
+		{"default sum", "a", "b\nc", "", 60, false},
+		{"sum", "a", "b", "sum", 30, false},
+		{"avg", "a", "b\nc", "avg", 20, false},
+		{"max", "a", "b\nc", "max", 30, false},
+		{"single target", "a", "", "", 0, true},
+		{"single target with aggregation", "a", "", "avg", 10, false},
+		{"tagged series target", "disk.used;datacenter=dc1;rack=a1", "", "avg", 10, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			metadata := map[string]string{"serverAddress": server.URL, "metricName": "request-count", "threshold": "100", "query": test.query, "additionalQueries": test.additional, "queryTime": "-30Seconds", "aggregation": test.aggregation}
+			meta, err := parseGraphiteMetadata(&ScalerConfig{TriggerMetadata: metadata})
+			if err != nil {
+				t.Fatal("Could not parse metadata:", err)
+			}
+			s := graphiteScaler{metadata: meta, httpClient: http.DefaultClient}
+
+			val, err := s.ExecuteGrapQuery(context.Background())
+			if test.isError {
+				if err == nil {
+					t.Error("Expected error but got success")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("Expected success but got error", err)
+			}
+			if val != test.expected {
+				t.Errorf("Expected %v but got %v", test.expected, val)
+			}
+			if len(targets) != len(meta.queries) || targets[0] != test.query {
+				t.Errorf("Expected %d targets starting with %q but got %v", len(meta.queries), test.query, targets)
+			}
+		})
+	}
+}
@@ -9,7 +9,9 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	v2beta2 "k8s.io/api/autoscaling/v2beta2"
@@ -39,6 +41,7 @@ type IBMMQMetadata struct {
 	host             string
 	queueManager     string
 	queueName        string
+	queuePattern     *regexp.Regexp
 	username         string
 	password         string
 	targetQueueDepth int
@@ -56,11 +59,15 @@ type Response struct {
 	Parameters Parameters `json:"parameters"`
 }
 
-// Parameters Contains the current depth of the IBM MQ Queue
+// Parameters Contains the name and current depth of the IBM MQ Queue
 type Parameters struct {
-	Curdepth int `json:"curdepth"`
+	Queue    string `json:"queue"`
+	Curdepth int    `json:"curdepth"`
 }
 
+// ibmMqMetricNameRegex matches the characters of a queueName pattern that can't be part of a metric name
+var ibmMqMetricNameRegex = regexp.MustCompile(`[^A-Za-z0-9-]+`)
+
 // NewIBMMQScaler creates a new IBM MQ scaler
 func NewIBMMQScaler(config *ScalerConfig) (Scaler, error) {
 	meta, err := parseIBMMQMetadata(config)
@@ -105,6 +112,20 @@ func parseIBMMQMetadata(config *ScalerConfig) (*IBMMQMetadata, error) {
 		return nil, fmt.Errorf("no queue name given")
 	}
 
+	if val, ok := config.TriggerMetadata["useRegex"]; ok && val != "" {
+		useRegex, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid useRegex setting: %s", err)
+		}
+		if useRegex {
+			pattern, err := regexp.Compile(fmt.Sprintf("^(?:%s)$", meta.queueName))
+			if err != nil {
+				return nil, fmt.Errorf("invalid queueName pattern: %s", err)
+			}
+			meta.queuePattern = pattern
+		}
+	}
+
 	if val, ok := config.TriggerMetadata["queueDepth"]; ok && val != "" {
 		queueDepth, err := strconv.Atoi(val)
 		if err != nil {
@@ -157,15 +178,41 @@ func (s *IBMMQScaler) IsActive(ctx context.Context) (bool, error) {
 	return queueDepth > 0, nil
 }
 
-// getQueueDepthViaHTTP returns the depth of the MQ Queue from the Admin endpoint
+// getQueueDepthViaHTTP returns the depth of the MQ Queue from the Admin endpoint, or the sum of the depths of the
+// queues matching the queueName pattern when useRegex is set
 func (s *IBMMQScaler) getQueueDepthViaHTTP(ctx context.Context) (int, error) {
-	queue := s.metadata.queueName
+	if s.metadata.queuePattern == nil {
+		response, err := s.displayQueues(ctx, s.metadata.queueName)
+		if err != nil {
+			return 0, err
+		}
+		return response.CommandResponse[0].Parameters.Curdepth, nil
+	}
+
+	// the queues are listed with an MQSC generic name built from the literal prefix of the pattern, which
+	// returns the name and depth of every match in a single response, then filtered with the full pattern
+	prefix, _ := s.metadata.queuePattern.LiteralPrefix()
+	response, err := s.displayQueues(ctx, prefix+"*")
+	if err != nil {
+		return 0, err
+	}
+	depth := 0
+	for _, queue := range response.CommandResponse {
+		if queue.Parameters.Queue != "" && s.metadata.queuePattern.MatchString(queue.Parameters.Queue) {
+			depth += queue.Parameters.Curdepth
+		}
+	}
+	return depth, nil
+}
+
+// displayQueues runs the MQSC DISPLAY QLOCAL command for the queue name, which may be generic, through the Admin endpoint
+func (s *IBMMQScaler) displayQueues(ctx context.Context, queue string) (*CommandResponse, error) {
 	url := s.metadata.host
 
 	var requestJSON = []byte(`{"type": "runCommandJSON", "command": "display", "qualifier": "qlocal", "name": "` + queue + `", "responseParameters" : ["CURDEPTH"]}`)
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(requestJSON))
 	if err != nil {
-		return 0, fmt.Errorf("failed to request queue depth: %s", err)
+		return nil, fmt.Errorf("failed to request queue depth: %s", err)
 	}
 	req.Header.Set("ibm-mq-rest-csrf-token", "value")
 	req.Header.Set("Content-Type", "application/json")
@@ -179,25 +226,25 @@ func (s *IBMMQScaler) getQueueDepthViaHTTP(ctx context.Context) (int, error) {
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return 0, fmt.Errorf("failed to contact MQ via REST: %s", err)
+		return nil, fmt.Errorf("failed to contact MQ via REST: %s", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return 0, fmt.Errorf("failed to ready body of request: %s", err)
+		return nil, fmt.Errorf("failed to ready body of request: %s", err)
 	}
 
 	var response CommandResponse
 	err = json.Unmarshal(body, &response)
 	if err != nil {
-		return 0, fmt.Errorf("failed to parse JSON: %s", err)
+		return nil, fmt.Errorf("failed to parse JSON: %s", err)
 	}
 
 	if response.CommandResponse == nil || len(response.CommandResponse) == 0 {
-		return 0, fmt.Errorf("failed to parse response from REST call: %s", err)
+		return nil, fmt.Errorf("failed to parse response from REST call: %s", err)
 	}
-	return response.CommandResponse[0].Parameters.Curdepth, nil
+	return &response, nil
 }
 
 // GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
@@ -205,7 +252,7 @@ func (s *IBMMQScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricS
 	targetQueueLengthQty := resource.NewQuantity(int64(s.metadata.targetQueueDepth), resource.DecimalSI)
 	externalMetric := &v2beta2.ExternalMetricSource{
 		Metric: v2beta2.MetricIdentifier{
-			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(fmt.Sprintf("ibmmq-%s", s.metadata.queueName))),
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, s.metricName()),
 		},
 		Target: v2beta2.MetricTarget{
 			Type:         v2beta2.AverageValueMetricType,
@@ -216,6 +263,14 @@ func (s *IBMMQScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricS
 	return []v2beta2.MetricSpec{metricSpec}
 }
 
+// metricName returns the name of the metric, the characters of a queueName pattern that aren't allowed are replaced
+func (s *IBMMQScaler) metricName() string {
+	if s.metadata.queuePattern != nil {
+		return fmt.Sprintf("ibmmq-%s", strings.Trim(ibmMqMetricNameRegex.ReplaceAllString(s.metadata.queueName, "-"), "-"))
+	}
+	return kedautil.NormalizeString(fmt.Sprintf("ibmmq-%s", s.metadata.queueName))
+}
+
 // GetMetrics returns value for a supported metric and an error if there is a problem getting the metric
 func (s *IBMMQScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
 	queueDepth, err := s.getQueueDepthViaHTTP(ctx)
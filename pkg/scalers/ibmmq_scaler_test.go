@@ -2,7 +2,11 @@ package scalers
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 )
@@ -36,6 +40,7 @@ type IBMMQMetricIdentifier struct {
 var IBMMQMetricIdentifiers = []IBMMQMetricIdentifier{
 	{&testIBMMQMetadata[1], 0, "s0-ibmmq-testQueue"},
 	{&testIBMMQMetadata[1], 1, "s1-ibmmq-testQueue"},
+	{&testIBMMQMetadata[10], 0, "s0-ibmmq-APP-ORDERS"},
 }
 
 // Test cases for TestIBMMQParseMetadata test
@@ -60,6 +65,12 @@ var testIBMMQMetadata = []parseIBMMQMetadataTestData{
 	{map[string]string{"host": testValidMQQueueURL, "queueManager": "testQueueManager", "queueName": "testQueue", "queueDepth": "10"}, true, map[string]string{"password": "Pass123"}},
 	// No password provided
 	{map[string]string{"host": testValidMQQueueURL, "queueManager": "testQueueManager", "queueName": "testQueue", "queueDepth": "10"}, true, map[string]string{"username": "testUsername"}},
+	// queueName pattern
+	{map[string]string{"host": testValidMQQueueURL, "queueManager": "testQueueManager", "queueName": `APP\.ORDERS\..*`, "useRegex": "true"}, false, map[string]string{"username": "testUsername", "password": "Pass123"}},
+	// Invalid queueName pattern
+	{map[string]string{"host": testValidMQQueueURL, "queueManager": "testQueueManager", "queueName": "APP.(ORDERS", "useRegex": "true"}, true, map[string]string{"username": "testUsername", "password": "Pass123"}},
+	// Invalid useRegex
+	{map[string]string{"host": testValidMQQueueURL, "queueManager": "testQueueManager", "queueName": "testQueue", "useRegex": "sometimes"}, true, map[string]string{"username": "testUsername", "password": "Pass123"}},
 }
 
 // Test MQ Connection metadata is parsed correctly
@@ -124,3 +135,41 @@ func TestIBMMQGetMetricSpecForScaling(t *testing.T) {
 		}
 	}
 }
+
+// Test that the depths of the queues matching the queueName pattern are summed
+func TestIBMMQGetQueueDepthWithRegex(t *testing.T) {
+	var names []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Error("Could not decode the request:", err)
+		}
+		names = append(names, request["name"].(string))
+		_, _ = io.WriteString(w, `{"commandResponse": [
+			{"completionCode": 0, "reasonCode": 0, "parameters": {"queue": "APP.ORDERS.EU", "curdepth": 3}},
+			{"completionCode": 0, "reasonCode": 0, "parameters": {"queue": "APP.ORDERS.US", "curdepth": 4}},
+			{"completionCode": 0, "reasonCode": 0, "parameters": {"queue": "APP.ORDERSX", "curdepth": 100}}
+		]}`)
+	}))
+	defer server.Close()
+
+	metadata, err := parseIBMMQMetadata(&ScalerConfig{
+		TriggerMetadata: map[string]string{"host": server.URL, "queueManager": "testQueueManager", "queueName": `APP\.ORDERS\..*`, "useRegex": "true"},
+		AuthParams:      map[string]string{"username": "testUsername", "password": "Pass123"},
+	})
+	if err != nil {
+		t.Fatal("Could not parse metadata:", err)
+	}
+	scaler := IBMMQScaler{metadata: metadata, defaultHTTPTimeout: time.Second}
+
+	depth, err := scaler.getQueueDepthViaHTTP(context.Background())
+	if err != nil {
+		t.Fatal("Expected success but got error", err)
+	}
+	if depth != 7 {
+		t.Errorf("Wrong queue depth %d, expected 7", depth)
+	}
+	if len(names) != 1 || names[0] != "APP.ORDERS.*" {
+		t.Errorf("Expected the queues to be listed once with a generic name, got %v", names)
+	}
+}
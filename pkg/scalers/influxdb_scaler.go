@@ -22,11 +22,18 @@ type influxDBScaler struct {
 	metadata *influxDBMetadata
 }
 
+const (
+	influxDBSumAggregation  = "sum"
+	influxDBLastAggregation = "last"
+	influxDBMaxAggregation  = "max"
+)
+
 type influxDBMetadata struct {
 	authToken        string
 	metricName       string
 	organizationName string
 	query            string
+	aggregation      string
 	serverURL        string
 	unsafeSsl        bool
 	thresholdValue   float64
@@ -60,6 +67,7 @@ func parseInfluxDBMetadata(config *ScalerConfig) (*influxDBMetadata, error) {
 	var metricName string
 	var organizationName string
 	var query string
+	var aggregation string
 	var serverURL string
 	var unsafeSsl bool
 	var thresholdValue float64
@@ -102,6 +110,15 @@ func parseInfluxDBMetadata(config *ScalerConfig) (*influxDBMetadata, error) {
 		return nil, fmt.Errorf("no query provided")
 	}
 
+	if val, ok := config.TriggerMetadata["aggregation"]; ok && val != "" {
+		switch val {
+		case influxDBSumAggregation, influxDBLastAggregation, influxDBMaxAggregation:
+			aggregation = val
+		default:
+			return nil, fmt.Errorf("invalid aggregation %q - must be one of sum, last or max", val)
+		}
+	}
+
 	if val, ok := config.TriggerMetadata["serverURL"]; ok {
 		serverURL = val
 	} else if val, ok := config.AuthParams["serverURL"]; ok {
@@ -139,6 +156,7 @@ func parseInfluxDBMetadata(config *ScalerConfig) (*influxDBMetadata, error) {
 		metricName:       metricName,
 		organizationName: organizationName,
 		query:            query,
+		aggregation:      aggregation,
 		serverURL:        serverURL,
 		thresholdValue:   thresholdValue,
 		unsafeSsl:        unsafeSsl,
@@ -150,7 +168,7 @@ func parseInfluxDBMetadata(config *ScalerConfig) (*influxDBMetadata, error) {
 func (s *influxDBScaler) IsActive(ctx context.Context) (bool, error) {
 	queryAPI := s.client.QueryAPI(s.metadata.organizationName)
 
-	value, err := queryInfluxDB(ctx, queryAPI, s.metadata.query)
+	value, err := queryInfluxDB(ctx, queryAPI, s.metadata.query, s.metadata.aggregation)
 	if err != nil {
 		return false, err
 	}
@@ -165,26 +183,69 @@ func (s *influxDBScaler) Close(context.Context) error {
 }
 
 // queryInfluxDB runs the query against the associated influxdb database
-// there is an implicit assumption here that the first value returned from the iterator
-// will be the value of interest
-func queryInfluxDB(ctx context.Context, queryAPI api.QueryAPI, query string) (float64, error) {
+// without an aggregation there is an implicit assumption here that the first value returned
+// from the iterator will be the value of interest, otherwise the values of all the rows of
+// all the tables are combined with the aggregation
+func queryInfluxDB(ctx context.Context, queryAPI api.QueryAPI, query string, aggregation string) (float64, error) {
 	result, err := queryAPI.Query(ctx, query)
 	if err != nil {
 		return 0, err
 	}
+	defer result.Close()
+
+	var value float64
+	rows := 0
+	for result.Next() {
+		record := result.Record()
+		rowValue, err := influxDBRecordValue(record.Values(), record.Table())
+		if err != nil {
+			return 0, err
+		}
+
+		switch {
+		case rows == 0, aggregation == influxDBLastAggregation:
+			value = rowValue
+		case aggregation == influxDBSumAggregation:
+			value += rowValue
+		case aggregation == influxDBMaxAggregation:
+			if rowValue > value {
+				value = rowValue
+			}
+		}
+		rows++
 
-	valueExists := result.Next()
-	if !valueExists {
+		if aggregation == "" {
+			return value, nil
+		}
+	}
+	if result.Err() != nil {
+		return 0, fmt.Errorf("error reading the results of the query: %s", result.Err())
+	}
+	if rows == 0 {
 		return 0, fmt.Errorf("no results found from query")
 	}
 
-	switch valRaw := result.Record().Value().(type) {
+	return value, nil
+}
+
+// influxDBRecordValue returns the _value column of a row of the table as a float
+func influxDBRecordValue(values map[string]interface{}, table int) (float64, error) {
+	valRaw, ok := values["_value"]
+	if !ok {
+		return 0, fmt.Errorf("no _value column in table %d of the query result, the query must return numeric values", table)
+	}
+
+	switch valRaw := valRaw.(type) {
 	case float64:
 		return valRaw, nil
 	case int64:
 		return float64(valRaw), nil
+	case uint64:
+		return float64(valRaw), nil
+	case nil:
+		return 0, fmt.Errorf("null value in table %d of the query result", table)
 	default:
-		return 0, fmt.Errorf("value of type %T could not be converted into a float", valRaw)
+		return 0, fmt.Errorf("value of type %T in table %d could not be converted into a float", valRaw, table)
 	}
 }
 
@@ -193,7 +254,7 @@ func (s *influxDBScaler) GetMetrics(ctx context.Context, metricName string, metr
 	// Grab QueryAPI to make queries to influxdb instance
 	queryAPI := s.client.QueryAPI(s.metadata.organizationName)
 
-	value, err := queryInfluxDB(ctx, queryAPI, s.metadata.query)
+	value, err := queryInfluxDB(ctx, queryAPI, s.metadata.query, s.metadata.aggregation)
 	if err != nil {
 		return []external_metrics.ExternalMetricValue{}, err
 	}
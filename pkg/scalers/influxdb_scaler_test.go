@@ -2,6 +2,10 @@ package scalers
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
@@ -45,6 +49,10 @@ var testInfluxDBMetadata = []parseInfluxDBMetadataTestData{
 	{map[string]string{"query": "from(bucket: hello)", "thresholdValue": "10", "unsafeSsl": "false"}, false, map[string]string{"serverURL": "https://influxdata.com", "organizationName": "influx_org", "authToken": "myToken"}},
 	// no sunsafeSsl value passed
 	{map[string]string{"serverURL": "https://influxdata.com", "metricName": "influx_metric", "organizationName": "influx_org", "query": "from(bucket: hello)", "thresholdValue": "10", "authToken": "myToken"}, false, map[string]string{}},
+	// aggregation
+	{map[string]string{"serverURL": "https://influxdata.com", "metricName": "influx_metric", "organizationName": "influx_org", "query": "from(bucket: hello)", "thresholdValue": "10", "authToken": "myToken", "aggregation": "sum"}, false, map[string]string{}},
+	// invalid aggregation
+	{map[string]string{"serverURL": "https://influxdata.com", "metricName": "influx_metric", "organizationName": "influx_org", "query": "from(bucket: hello)", "thresholdValue": "10", "authToken": "myToken", "aggregation": "avg"}, true, map[string]string{}},
 }
 
 var influxDBMetricIdentifiers = []influxDBMetricIdentifier{
@@ -81,3 +89,60 @@ func TestInfluxDBGetMetricSpecForScaling(t *testing.T) {
 		}
 	}
 }
+
+// influxDBTestCSV builds an annotated CSV response with a _value column of the given type, one table per values slice
+func influxDBTestCSV(valueType string, tables ...[]string) string {
+	csv := fmt.Sprintf("#datatype,string,long,%s\n#group,false,false,false\n#default,_result,,\n,result,table,_value\n", valueType)
+	for table, values := range tables {
+		for _, value := range values {
+			csv += fmt.Sprintf(",,%d,%s\n", table, value)
+		}
+	}
+	return csv
+}
+
+func TestInfluxDBQueryAggregation(t *testing.T) {
+	testCases := []struct {
+		name        string
+		response    string
+		aggregation string
+		expected    float64
+		errContains string
+	}{
+		{"first row without aggregation", influxDBTestCSV("double", []string{"2", "3"}, []string{"5"}), "", 2, ""},
+		{"sum", influxDBTestCSV("double", []string{"2", "3"}, []string{"5"}), "sum", 10, ""},
+		{"last", influxDBTestCSV("double", []string{"2", "7"}, []string{"5"}), "last", 5, ""},
+		{"max", influxDBTestCSV("long", []string{"2", "7"}, []string{"5"}), "max", 7, ""},
+		{"unsigned values", influxDBTestCSV("unsignedLong", []string{"2"}, []string{"5"}), "sum", 7, ""},
+		{"no rows", influxDBTestCSV("double"), "sum", 0, "no results found"},
+		{"string values", influxDBTestCSV("string", []string{"a"}), "sum", 0, "could not be converted"},
+		{"no _value column", "#datatype,string,long,double\n#group,false,false,false\n#default,_result,,\n,result,table,count\n,,0,1\n", "sum", 0, "no _value column"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/csv")
+				_, _ = w.Write([]byte(testCase.response))
+			}))
+			defer server.Close()
+
+			client := influxdb2.NewClient(server.URL, "myToken")
+			defer client.Close()
+
+			value, err := queryInfluxDB(context.Background(), client.QueryAPI("influx_org"), "from(bucket: hello)", testCase.aggregation)
+			if testCase.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), testCase.errContains) {
+					t.Errorf("Expected error containing %q but got %v", testCase.errContains, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("Expected success but got error", err)
+			}
+			if value != testCase.expected {
+				t.Errorf("Expected %v but got %v", testCase.expected, value)
+			}
+		})
+	}
+}
@@ -26,13 +26,15 @@ type kafkaScaler struct {
 }
 
 type kafkaMetadata struct {
-	bootstrapServers   []string
-	group              string
-	topic              string
-	lagThreshold       int64
-	offsetResetPolicy  offsetResetPolicy
-	allowIdleConsumers bool
-	version            sarama.KafkaVersion
+	bootstrapServers []string
+	group            string
+	topic            string
+	lagThreshold     int64
+	// scale on the lag of the most lagging partition instead of the total lag when set
+	partitionLagThreshold int64
+	offsetResetPolicy     offsetResetPolicy
+	allowIdleConsumers    bool
+	version               sarama.KafkaVersion
 
 	// SASL
 	saslType kafkaSaslType
@@ -66,11 +68,12 @@ const (
 )
 
 const (
-	lagThresholdMetricName   = "lagThreshold"
-	kafkaMetricType          = "External"
-	defaultKafkaLagThreshold = 10
-	defaultOffsetResetPolicy = latest
-	invalidOffset            = -1
+	lagThresholdMetricName          = "lagThreshold"
+	partitionLagThresholdMetricName = "partitionLagThreshold"
+	kafkaMetricType                 = "External"
+	defaultKafkaLagThreshold        = 10
+	defaultOffsetResetPolicy        = latest
+	invalidOffset                   = -1
 )
 
 var kafkaLog = logf.Log.WithName("kafka_scaler")
@@ -145,6 +148,17 @@ func parseKafkaMetadata(config *ScalerConfig) (kafkaMetadata, error) {
 		meta.lagThreshold = t
 	}
 
+	if val, ok := config.TriggerMetadata[partitionLagThresholdMetricName]; ok {
+		t, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return meta, fmt.Errorf("error parsing %s: %s", partitionLagThresholdMetricName, err)
+		}
+		if t <= 0 {
+			return meta, fmt.Errorf("%s must be a positive number", partitionLagThresholdMetricName)
+		}
+		meta.partitionLagThreshold = t
+	}
+
 	meta.saslType = KafkaSASLTypeNone
 	if val, ok := config.AuthParams["sasl"]; ok {
 		val = strings.TrimSpace(val)
@@ -366,8 +380,16 @@ func (s *kafkaScaler) Close(context.Context) error {
 	return nil
 }
 
+// threshold returns the target of the metric, the per partition threshold when scaling on the most lagging partition
+func (s *kafkaScaler) threshold() int64 {
+	if s.metadata.partitionLagThreshold > 0 {
+		return s.metadata.partitionLagThreshold
+	}
+	return s.metadata.lagThreshold
+}
+
 func (s *kafkaScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
-	targetMetricValue := resource.NewQuantity(s.metadata.lagThreshold, resource.DecimalSI)
+	targetMetricValue := resource.NewQuantity(s.threshold(), resource.DecimalSI)
 
 	var metricName string
 	if s.metadata.topic != "" {
@@ -437,32 +459,47 @@ func (s *kafkaScaler) GetMetrics(ctx context.Context, metricName string, metricS
 		return []external_metrics.ExternalMetricValue{}, err
 	}
 
+	lag := s.getLag(consumerOffsets, producerOffsets)
+	kafkaLog.V(1).Info(fmt.Sprintf("Kafka scaler: Providing metrics based on lag %v, topicPartitions %v, threshold %v", lag, len(topicPartitions), s.threshold()))
+
+	metric := external_metrics.ExternalMetricValue{
+		MetricName: metricName,
+		Value:      *resource.NewQuantity(lag, resource.DecimalSI),
+		Timestamp:  metav1.Now(),
+	}
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
+
+// getLag returns the total lag of the partitions, or the lag of the most lagging partition when partitionLagThreshold is set
+func (s *kafkaScaler) getLag(consumerOffsets *sarama.OffsetFetchResponse, producerOffsets map[string]map[int32]int64) int64 {
 	totalLag := int64(0)
+	maxPartitionLag := int64(0)
 	totalTopicPartitions := int64(0)
 
 	for topic, partitionsOffsets := range producerOffsets {
 		for partition := range partitionsOffsets {
 			lag, _ := s.getLagForPartition(topic, partition, consumerOffsets, producerOffsets)
 			totalLag += lag
+			if lag > maxPartitionLag {
+				maxPartitionLag = lag
+			}
 		}
 		totalTopicPartitions += (int64)(len(partitionsOffsets))
 	}
-	kafkaLog.V(1).Info(fmt.Sprintf("Kafka scaler: Providing metrics based on totalLag %v, topicPartitions %v, threshold %v", totalLag, len(topicPartitions), s.metadata.lagThreshold))
+
+	lag := totalLag
+	if s.metadata.partitionLagThreshold > 0 {
+		lag = maxPartitionLag
+	}
 
 	if !s.metadata.allowIdleConsumers {
 		// don't scale out beyond the number of topicPartitions
-		if (totalLag / s.metadata.lagThreshold) > totalTopicPartitions {
-			totalLag = totalTopicPartitions * s.metadata.lagThreshold
+		if (lag / s.threshold()) > totalTopicPartitions {
+			lag = totalTopicPartitions * s.threshold()
 		}
 	}
-
-	metric := external_metrics.ExternalMetricValue{
-		MetricName: metricName,
-		Value:      *resource.NewQuantity(totalLag, resource.DecimalSI),
-		Timestamp:  metav1.Now(),
-	}
-
-	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+	return lag
 }
 
 type brokerOffsetResult struct {
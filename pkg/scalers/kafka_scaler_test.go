@@ -4,6 +4,8 @@ import (
 	"context"
 	"reflect"
 	"testing"
+
+	"github.com/Shopify/sarama"
 )
 
 type parseKafkaMetadataTestData struct {
@@ -209,3 +211,72 @@ func TestKafkaGetMetricSpecForScaling(t *testing.T) {
 		}
 	}
 }
+
+func TestKafkaPartitionLagThreshold(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		metadata              map[string]string
+		partitionLagThreshold int64
+		isError               bool
+	}{
+		{"not set", map[string]string{}, 0, false},
+		{"set", map[string]string{"partitionLagThreshold": "5"}, 5, false},
+		{"zero", map[string]string{"partitionLagThreshold": "0"}, 0, true},
+		{"negative", map[string]string{"partitionLagThreshold": "-1"}, 0, true},
+		{"not a number", map[string]string{"partitionLagThreshold": "AA"}, 0, true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			metadata := map[string]string{}
+			for key, value := range validKafkaMetadata {
+				metadata[key] = value
+			}
+			for key, value := range testCase.metadata {
+				metadata[key] = value
+			}
+			meta, err := parseKafkaMetadata(&ScalerConfig{TriggerMetadata: metadata, AuthParams: validWithoutAuthParams})
+			if testCase.isError {
+				if err == nil {
+					t.Error("Expected error but got success")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("Expected success but got error", err)
+			}
+			if meta.partitionLagThreshold != testCase.partitionLagThreshold {
+				t.Errorf("Expected partitionLagThreshold %d but got %d", testCase.partitionLagThreshold, meta.partitionLagThreshold)
+			}
+		})
+	}
+}
+
+func TestKafkaGetLag(t *testing.T) {
+	// partition 0 has a lag of 2, partition 1 of 12 and partition 2 of 1
+	consumerOffsets := &sarama.OffsetFetchResponse{}
+	consumerOffsets.AddBlock("my-topic", 0, &sarama.OffsetFetchResponseBlock{Offset: 8})
+	consumerOffsets.AddBlock("my-topic", 1, &sarama.OffsetFetchResponseBlock{Offset: 8})
+	consumerOffsets.AddBlock("my-topic", 2, &sarama.OffsetFetchResponseBlock{Offset: 9})
+	producerOffsets := map[string]map[int32]int64{"my-topic": {0: 10, 1: 20, 2: 10}}
+
+	testCases := []struct {
+		name     string
+		metadata kafkaMetadata
+		lag      int64
+	}{
+		{"total lag by default", kafkaMetadata{lagThreshold: 10, allowIdleConsumers: true}, 15},
+		{"lag of the most lagging partition", kafkaMetadata{lagThreshold: 10, partitionLagThreshold: 5, allowIdleConsumers: true}, 12},
+		{"total lag capped to the partitions", kafkaMetadata{lagThreshold: 2}, 6},
+		{"partition lag capped to the partitions", kafkaMetadata{lagThreshold: 10, partitionLagThreshold: 2}, 6},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			s := kafkaScaler{metadata: testCase.metadata}
+			if lag := s.getLag(consumerOffsets, producerOffsets); lag != testCase.lag {
+				t.Errorf("Expected lag %d but got %d", testCase.lag, lag)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
@@ -53,8 +54,11 @@ type mongoDBMetadata struct {
 	// +required
 	collection string
 	// A mongoDB filter doc,used by specify DB.
-	// +required
+	// +optional, either query or pipeline is required
 	query string
+	// A mongoDB aggregation pipeline returning a single document with a single numeric field, used instead of query.
+	// +optional
+	pipeline []bson.D
 	// A threshold that is used as targetAverageValue in HPA
 	// +required
 	queryValue int
@@ -113,10 +117,20 @@ func parseMongoDBMetadata(config *ScalerConfig) (*mongoDBMetadata, string, error
 		return nil, "", fmt.Errorf("no collection given")
 	}
 
-	if val, ok := config.TriggerMetadata["query"]; ok {
-		meta.query = val
-	} else {
-		return nil, "", fmt.Errorf("no query given")
+	query, hasQuery := config.TriggerMetadata["query"]
+	pipeline, hasPipeline := config.TriggerMetadata["pipeline"]
+	switch {
+	case hasQuery && hasPipeline:
+		return nil, "", fmt.Errorf("query and pipeline can not be used together")
+	case hasQuery:
+		meta.query = query
+	case hasPipeline:
+		meta.pipeline, err = json2BsonPipeline(pipeline)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid pipeline, because of %v", err)
+		}
+	default:
+		return nil, "", fmt.Errorf("no query or pipeline given")
 	}
 
 	if val, ok := config.TriggerMetadata["queryValue"]; ok {
@@ -207,11 +221,15 @@ func (s *mongoDBScaler) Close(ctx context.Context) error {
 	return nil
 }
 
-// getQueryResult query mongoDB by meta.query
-func (s *mongoDBScaler) getQueryResult(ctx context.Context) (int, error) {
+// getQueryResult query mongoDB by meta.query, or runs meta.pipeline
+func (s *mongoDBScaler) getQueryResult(ctx context.Context) (float64, error) {
 	ctx, cancel := context.WithTimeout(ctx, mongoDBDefaultTimeOut)
 	defer cancel()
 
+	if s.metadata.pipeline != nil {
+		return s.getPipelineResult(ctx)
+	}
+
 	filter, err := json2BsonDoc(s.metadata.query)
 	if err != nil {
 		mongoDBLog.Error(err, fmt.Sprintf("failed to convert query param to bson.Doc, because of %v", err))
@@ -224,7 +242,67 @@ func (s *mongoDBScaler) getQueryResult(ctx context.Context) (int, error) {
 		return 0, err
 	}
 
-	return int(docsNum), nil
+	return float64(docsNum), nil
+}
+
+// getPipelineResult runs the aggregation pipeline by meta.pipeline and returns its numeric result
+func (s *mongoDBScaler) getPipelineResult(ctx context.Context) (float64, error) {
+	cursor, err := s.client.Database(s.metadata.dbName).Collection(s.metadata.collection).Aggregate(ctx, s.metadata.pipeline)
+	if err != nil {
+		mongoDBLog.Error(err, fmt.Sprintf("failed to aggregate %v in %v, because of %v", s.metadata.dbName, s.metadata.collection, err))
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []bson.Raw
+	for cursor.Next(ctx) {
+		docs = append(docs, cursor.Current)
+	}
+	if err := cursor.Err(); err != nil {
+		mongoDBLog.Error(err, fmt.Sprintf("failed to read the aggregation result of %v in %v, because of %v", s.metadata.dbName, s.metadata.collection, err))
+		return 0, err
+	}
+	return getMongoDBPipelineValue(docs)
+}
+
+// getMongoDBPipelineValue returns the single numeric field, besides _id, of the single document an aggregation
+// pipeline returned. No document, e.g. a $group after a $match without match, counts as 0.
+func getMongoDBPipelineValue(docs []bson.Raw) (float64, error) {
+	switch len(docs) {
+	case 0:
+		return 0, nil
+	case 1:
+	default:
+		return 0, fmt.Errorf("the pipeline returned %d documents, expected a single one", len(docs))
+	}
+
+	elements, err := docs[0].Elements()
+	if err != nil {
+		return 0, err
+	}
+	var fields []bson.RawElement
+	for _, element := range elements {
+		if element.Key() != "_id" {
+			fields = append(fields, element)
+		}
+	}
+	if len(fields) != 1 {
+		return 0, fmt.Errorf("the pipeline returned %s, expected a single numeric field", docs[0])
+	}
+
+	value := fields[0].Value()
+	switch value.Type {
+	case bsontype.Int32:
+		return float64(value.Int32()), nil
+	case bsontype.Int64:
+		return float64(value.Int64()), nil
+	case bsontype.Double:
+		return value.Double(), nil
+	case bsontype.Decimal128:
+		return strconv.ParseFloat(value.Decimal128().String(), 64)
+	default:
+		return 0, fmt.Errorf("the field %s returned by the pipeline is a %s, expected a number", fields[0].Key(), value.Type)
+	}
 }
 
 // GetMetrics query from mongoDB,and return to external metrics
@@ -236,7 +314,7 @@ func (s *mongoDBScaler) GetMetrics(ctx context.Context, metricName string, metri
 
 	metric := external_metrics.ExternalMetricValue{
 		MetricName: metricName,
-		Value:      *resource.NewQuantity(int64(num), resource.DecimalSI),
+		Value:      *resource.NewMilliQuantity(int64(num*1000), resource.DecimalSI),
 		Timestamp:  metav1.Now(),
 	}
 
@@ -262,6 +340,21 @@ func (s *mongoDBScaler) GetMetricSpecForScaling(context.Context) []v2beta2.Metri
 	return []v2beta2.MetricSpec{metricSpec}
 }
 
+// json2BsonPipeline convert a Json array of stages to an aggregation pipeline
+func json2BsonPipeline(js string) ([]bson.D, error) {
+	var pipeline struct {
+		Stages []bson.D `bson:"stages"`
+	}
+	// only documents can be unmarshaled, the array is wrapped in one
+	if err := bson.UnmarshalExtJSON([]byte(`{"stages":`+js+`}`), true, &pipeline); err != nil {
+		return nil, err
+	}
+	if len(pipeline.Stages) == 0 {
+		return nil, errors.New("empty pipeline")
+	}
+	return pipeline.Stages, nil
+}
+
 // json2BsonDoc convert Json to Bson.Doc
 func json2BsonDoc(js string) (doc bsonx.Doc, err error) {
 	doc = bsonx.Doc{}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"testing"
 
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
@@ -54,6 +56,41 @@ var testMONGODBMetadata = []parseMongoDBMetadataTestData{
 		resolvedEnv: testMongoDBResolvedEnv,
 		raisesError: false,
 	},
+	// with pipeline
+	{
+		metadata:    map[string]string{"pipeline": `[{"$match":{"status":"pending"}},{"$group":{"_id":null,"total":{"$sum":"$items"}}}]`, "collection": "demo", "queryValue": "12", "connectionStringFromEnv": "Mongo_CONN_STR", "dbName": "test"},
+		authParams:  map[string]string{},
+		resolvedEnv: testMongoDBResolvedEnv,
+		raisesError: false,
+	},
+	// invalid pipeline
+	{
+		metadata:    map[string]string{"pipeline": `{"$match":{"status":"pending"}}`, "collection": "demo", "queryValue": "12", "connectionStringFromEnv": "Mongo_CONN_STR", "dbName": "test"},
+		authParams:  map[string]string{},
+		resolvedEnv: testMongoDBResolvedEnv,
+		raisesError: true,
+	},
+	// empty pipeline
+	{
+		metadata:    map[string]string{"pipeline": `[]`, "collection": "demo", "queryValue": "12", "connectionStringFromEnv": "Mongo_CONN_STR", "dbName": "test"},
+		authParams:  map[string]string{},
+		resolvedEnv: testMongoDBResolvedEnv,
+		raisesError: true,
+	},
+	// both query and pipeline
+	{
+		metadata:    map[string]string{"query": `{"name":"John"}`, "pipeline": `[{"$count":"total"}]`, "collection": "demo", "queryValue": "12", "connectionStringFromEnv": "Mongo_CONN_STR", "dbName": "test"},
+		authParams:  map[string]string{},
+		resolvedEnv: testMongoDBResolvedEnv,
+		raisesError: true,
+	},
+	// neither query nor pipeline
+	{
+		metadata:    map[string]string{"collection": "demo", "queryValue": "12", "connectionStringFromEnv": "Mongo_CONN_STR", "dbName": "test"},
+		authParams:  map[string]string{},
+		resolvedEnv: testMongoDBResolvedEnv,
+		raisesError: true,
+	},
 }
 
 var mongoDBMetricIdentifiers = []mongoDBMetricIdentifier{
@@ -99,3 +136,49 @@ func TestJson2BsonDoc(t *testing.T) {
 		t.Error("the doc is nil")
 	}
 }
+
+func TestGetMongoDBPipelineValue(t *testing.T) {
+	decimal, _ := primitive.ParseDecimal128("2.5")
+	testCases := []struct {
+		name     string
+		docs     []bson.M
+		expected float64
+		isError  bool
+	}{
+		{name: "no document", docs: nil, expected: 0},
+		{name: "int32", docs: []bson.M{{"_id": nil, "total": int32(7)}}, expected: 7},
+		{name: "int64", docs: []bson.M{{"total": int64(8)}}, expected: 8},
+		{name: "double", docs: []bson.M{{"_id": "pending", "total": 1.5}}, expected: 1.5},
+		{name: "decimal", docs: []bson.M{{"_id": nil, "total": decimal}}, expected: 2.5},
+		{name: "several documents", docs: []bson.M{{"total": 1}, {"total": 2}}, isError: true},
+		{name: "several fields", docs: []bson.M{{"_id": nil, "total": 1, "count": 2}}, isError: true},
+		{name: "no field", docs: []bson.M{{"_id": nil}}, isError: true},
+		{name: "not a number", docs: []bson.M{{"_id": nil, "total": "7"}}, isError: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var docs []bson.Raw
+			for _, doc := range testCase.docs {
+				raw, err := bson.Marshal(doc)
+				if err != nil {
+					t.Fatal(err)
+				}
+				docs = append(docs, raw)
+			}
+			value, err := getMongoDBPipelineValue(docs)
+			if testCase.isError {
+				if err == nil {
+					t.Error("Expected error but got success")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("Expected success but got error", err)
+			}
+			if value != testCase.expected {
+				t.Errorf("Wrong value %v, expected %v", value, testCase.expected)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-sql-driver/mysql"
 	"k8s.io/api/autoscaling/v2beta2"
@@ -19,7 +20,8 @@ import (
 )
 
 type mySQLScaler struct {
-	metadata   *mySQLMetadata
+	metadata *mySQLMetadata
+	// connection is the pool of the scaler, kept open for as long as the scaler is cached and closed in Close
 	connection *sql.DB
 }
 
@@ -33,6 +35,10 @@ type mySQLMetadata struct {
 	query            string
 	queryValue       int
 	metricName       string
+	// connection pool settings, only applied when set
+	maxIdleConns    *int
+	maxOpenConns    *int
+	connMaxLifetime time.Duration
 }
 
 var mySQLLog = logf.Log.WithName("mysql_scaler")
@@ -112,6 +118,28 @@ func parseMySQLMetadata(config *ScalerConfig) (*mySQLMetadata, error) {
 		}
 	}
 
+	for _, key := range []string{"maxIdleConns", "maxOpenConns"} {
+		if val, ok := config.TriggerMetadata[key]; ok && val != "" {
+			conns, err := strconv.Atoi(val)
+			if err != nil || conns < 0 {
+				return nil, fmt.Errorf("%s parsing error - must be a non-negative integer", key)
+			}
+			if key == "maxIdleConns" {
+				meta.maxIdleConns = &conns
+			} else {
+				meta.maxOpenConns = &conns
+			}
+		}
+	}
+
+	if val, ok := config.TriggerMetadata["connMaxLifetime"]; ok && val != "" {
+		lifetime, err := strconv.Atoi(val)
+		if err != nil || lifetime < 0 {
+			return nil, fmt.Errorf("connMaxLifetime parsing error - must be a non-negative number of seconds")
+		}
+		meta.connMaxLifetime = time.Duration(lifetime) * time.Second
+	}
+
 	if meta.connectionString != "" {
 		meta.dbName = parseMySQLDbNameFromConnectionStr(meta.connectionString)
 	}
@@ -141,8 +169,7 @@ func metadataToConnectionStr(meta *mySQLMetadata) string {
 
 // newMySQLConnection creates MySQL db connection
 func newMySQLConnection(meta *mySQLMetadata) (*sql.DB, error) {
-	connStr := metadataToConnectionStr(meta)
-	db, err := sql.Open("mysql", connStr)
+	db, err := openMySQLPool(meta)
 	if err != nil {
 		mySQLLog.Error(err, fmt.Sprintf("Found error when opening connection: %s", err))
 		return nil, err
@@ -150,11 +177,30 @@ func newMySQLConnection(meta *mySQLMetadata) (*sql.DB, error) {
 	err = db.Ping()
 	if err != nil {
 		mySQLLog.Error(err, fmt.Sprintf("Found error when pinging database: %s", err))
+		_ = db.Close()
 		return nil, err
 	}
 	return db, nil
 }
 
+// openMySQLPool opens the connection pool of the metadata with its pool settings, without connecting yet
+func openMySQLPool(meta *mySQLMetadata) (*sql.DB, error) {
+	db, err := sql.Open("mysql", metadataToConnectionStr(meta))
+	if err != nil {
+		return nil, err
+	}
+	if meta.maxIdleConns != nil {
+		db.SetMaxIdleConns(*meta.maxIdleConns)
+	}
+	if meta.maxOpenConns != nil {
+		db.SetMaxOpenConns(*meta.maxOpenConns)
+	}
+	if meta.connMaxLifetime > 0 {
+		db.SetConnMaxLifetime(meta.connMaxLifetime)
+	}
+	return db, nil
+}
+
 // parseMySQLDbNameFromConnectionStr returns dbname from connection string
 // in it is not able to parse it, it returns "dbname" string
 func parseMySQLDbNameFromConnectionStr(connectionString string) string {
@@ -1,6 +1,8 @@
 package scalers
 
 import (
+	"context"
+	"strings"
 	"testing"
 )
 
@@ -51,6 +53,27 @@ var testMySQLMetadata = []parseMySQLMetadataTestData{
 		resolvedEnv: testMySQLResolvedEnv,
 		raisesError: false,
 	},
+	// Connection pool settings
+	{
+		metadata:    map[string]string{"query": "query", "queryValue": "12", "connectionStringFromEnv": "MYSQL_CONN_STR", "maxIdleConns": "5", "maxOpenConns": "10", "connMaxLifetime": "300"},
+		authParams:  map[string]string{},
+		resolvedEnv: testMySQLResolvedEnv,
+		raisesError: false,
+	},
+	// Invalid maxIdleConns
+	{
+		metadata:    map[string]string{"query": "query", "queryValue": "12", "connectionStringFromEnv": "MYSQL_CONN_STR", "maxIdleConns": "-1"},
+		authParams:  map[string]string{},
+		resolvedEnv: testMySQLResolvedEnv,
+		raisesError: true,
+	},
+	// Invalid connMaxLifetime
+	{
+		metadata:    map[string]string{"query": "query", "queryValue": "12", "connectionStringFromEnv": "MYSQL_CONN_STR", "connMaxLifetime": "5m"},
+		authParams:  map[string]string{},
+		resolvedEnv: testMySQLResolvedEnv,
+		raisesError: true,
+	},
 }
 
 var mySQLMetricIdentifiers = []mySQLMetricIdentifier{
@@ -102,3 +125,38 @@ func TestMySQLGetMetricSpecForScaling(t *testing.T) {
 		}
 	}
 }
+
+func TestMySQLConnectionPool(t *testing.T) {
+	testMeta := map[string]string{"query": "query", "queryValue": "12", "connectionStringFromEnv": "MYSQL_CONN_STR", "maxIdleConns": "5", "maxOpenConns": "7"}
+	meta, err := parseMySQLMetadata(&ScalerConfig{ResolvedEnv: testMySQLResolvedEnv, TriggerMetadata: testMeta, AuthParams: map[string]string{}})
+	if err != nil {
+		t.Fatal("Could not parse metadata:", err)
+	}
+
+	// opening the pool does not connect to the database
+	first, err := openMySQLPool(meta)
+	if err != nil {
+		t.Fatal("Could not open the pool:", err)
+	}
+	if maxOpen := first.Stats().MaxOpenConnections; maxOpen != 7 {
+		t.Errorf("Wrong maxOpenConns %d, expected 7", maxOpen)
+	}
+
+	// every scaler has its own pool, even with the same connection string
+	second, err := openMySQLPool(meta)
+	if err != nil {
+		t.Fatal("Could not open the pool:", err)
+	}
+	if first == second {
+		t.Error("Expected the scalers not to share their pool")
+	}
+	defer second.Close()
+
+	scaler := mySQLScaler{metadata: meta, connection: first}
+	if err := scaler.Close(context.Background()); err != nil {
+		t.Fatal("Could not close the scaler:", err)
+	}
+	if err := first.PingContext(context.Background()); err == nil || !strings.Contains(err.Error(), "closed") {
+		t.Errorf("Expected the pool to be closed with the scaler, got %v", err)
+	}
+}
@@ -5,9 +5,10 @@ import (
 	"database/sql"
 	"fmt"
 	"strconv"
+	"strings"
 
 	// PostreSQL drive required for this scaler
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"k8s.io/api/autoscaling/v2beta2"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -70,10 +71,15 @@ func parsePostgreSQLMetadata(config *ScalerConfig) (*postgreSQLMetadata, error)
 	}
 
 	switch {
-	case config.AuthParams["connection"] != "":
-		meta.connection = config.AuthParams["connection"]
-	case config.TriggerMetadata["connectionFromEnv"] != "":
-		meta.connection = config.ResolvedEnv[config.TriggerMetadata["connectionFromEnv"]]
+	case config.AuthParams["connection"] != "", config.TriggerMetadata["connectionFromEnv"] != "":
+		connection := config.AuthParams["connection"]
+		if connection == "" {
+			connection = config.ResolvedEnv[config.TriggerMetadata["connectionFromEnv"]]
+		}
+		var err error
+		if meta.connection, err = appendPostgreSQLTLSParams(config, connection); err != nil {
+			return nil, err
+		}
 	default:
 		host, err := GetFromAuthOrMeta(config, "host")
 		if err != nil {
@@ -116,6 +122,12 @@ func parsePostgreSQLMetadata(config *ScalerConfig) (*postgreSQLMetadata, error)
 			sslmode,
 			password,
 		)
+
+		tlsParams, err := parsePostgreSQLTLSParams(config, sslmode)
+		if err != nil {
+			return nil, err
+		}
+		meta.connection += tlsParams
 	}
 
 	if val, ok := config.TriggerMetadata["metricName"]; ok {
@@ -127,6 +139,61 @@ func parsePostgreSQLMetadata(config *ScalerConfig) (*postgreSQLMetadata, error)
 	return &meta, nil
 }
 
+// appendPostgreSQLTLSParams appends the TLS parameters of the trigger authentication to a connection string given
+// as is, a postgres:// URL is turned into keyword/value pairs first. Without TLS parameters it is left untouched.
+func appendPostgreSQLTLSParams(config *ScalerConfig, connection string) (string, error) {
+	if config.AuthParams["sslcert"] == "" && config.AuthParams["sslkey"] == "" && config.AuthParams["sslrootcert"] == "" {
+		return connection, nil
+	}
+	if strings.HasPrefix(connection, "postgres://") || strings.HasPrefix(connection, "postgresql://") {
+		var err error
+		if connection, err = pq.ParseURL(connection); err != nil {
+			return "", fmt.Errorf("error parsing the postgreSQL connection URL to add sslcert, sslkey and sslrootcert: %s", err)
+		}
+	}
+
+	var sslmode string
+	for _, param := range strings.Fields(connection) {
+		if strings.HasPrefix(param, "sslmode=") {
+			sslmode = strings.Trim(strings.TrimPrefix(param, "sslmode="), "'")
+		}
+	}
+	tlsParams, err := parsePostgreSQLTLSParams(config, sslmode)
+	if err != nil {
+		return "", err
+	}
+	return connection + tlsParams, nil
+}
+
+// parsePostgreSQLTLSParams returns the libpq parameters of the client certificate and CA files given in the
+// trigger authentication, to append to the connection string
+func parsePostgreSQLTLSParams(config *ScalerConfig, sslmode string) (string, error) {
+	sslcert, sslkey, sslrootcert := config.AuthParams["sslcert"], config.AuthParams["sslkey"], config.AuthParams["sslrootcert"]
+	if (sslcert == "") != (sslkey == "") {
+		return "", fmt.Errorf("both sslcert and sslkey must be given for client certificate authentication")
+	}
+	if (sslcert != "" || sslrootcert != "") && sslmode == "disable" {
+		return "", fmt.Errorf("sslcert, sslkey and sslrootcert can not be used with sslmode disable")
+	}
+
+	var params strings.Builder
+	for _, param := range []struct{ name, value string }{{"sslcert", sslcert}, {"sslkey", sslkey}, {"sslrootcert", sslrootcert}} {
+		if param.value != "" {
+			fmt.Fprintf(&params, " %s=%s", param.name, escapePostgreSQLConnectionValue(param.value))
+		}
+	}
+	return params.String(), nil
+}
+
+// escapePostgreSQLConnectionValue quotes a connection string value containing spaces, quotes or backslashes
+// as described in https://www.postgresql.org/docs/current/libpq-connect.html#LIBPQ-CONNSTRING
+func escapePostgreSQLConnectionValue(value string) string {
+	if !strings.ContainsAny(value, ` '\`) {
+		return value
+	}
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
 func getConnection(meta *postgreSQLMetadata) (*sql.DB, error) {
 	db, err := sql.Open("postgres", meta.connection)
 	if err != nil {
@@ -67,6 +67,14 @@ var testPostgreSQLConnectionstring = []postgreSQLConnectionStringTestData{
 	{metadata: map[string]string{"query": "test_query", "targetQueryValue": "5"}, authParam: map[string]string{"connection": "test_connection_from_auth"}, connectionString: "test_connection_from_auth"},
 	// from meta
 	{metadata: map[string]string{"query": "test_query", "targetQueryValue": "5", "host": "localhost", "port": "1234", "dbName": "testDb", "userName": "user", "sslmode": "required"}, connectionString: "host=localhost port=1234 user=user dbname=testDb sslmode=required password="},
+	// client certificate from authentication
+	{metadata: map[string]string{"query": "test_query", "targetQueryValue": "5", "host": "localhost", "port": "1234", "dbName": "testDb", "userName": "user", "sslmode": "verify-full"}, authParam: map[string]string{"sslcert": "/certs/client.crt", "sslkey": "/certs/client.key", "sslrootcert": "/certs/ca.crt"}, connectionString: "host=localhost port=1234 user=user dbname=testDb sslmode=verify-full password= sslcert=/certs/client.crt sslkey=/certs/client.key sslrootcert=/certs/ca.crt"},
+	// client certificate added to a connection string
+	{metadata: map[string]string{"query": "test_query", "targetQueryValue": "5", "connectionFromEnv": "CONNECTION_ENV"}, resolvedEnv: map[string]string{"CONNECTION_ENV": "host=localhost sslmode=verify-full"}, authParam: map[string]string{"sslcert": "/certs/client.crt", "sslkey": "/certs/client.key"}, connectionString: "host=localhost sslmode=verify-full sslcert=/certs/client.crt sslkey=/certs/client.key"},
+	// CA added to a connection URL
+	{metadata: map[string]string{"query": "test_query", "targetQueryValue": "5"}, authParam: map[string]string{"connection": "postgres://user@localhost:1234/testDb?sslmode=verify-ca", "sslrootcert": "/certs/ca.crt"}, connectionString: "dbname='testDb' host='localhost' port='1234' sslmode='verify-ca' user='user' sslrootcert=/certs/ca.crt"},
+	// certificate paths needing escaping
+	{metadata: map[string]string{"query": "test_query", "targetQueryValue": "5", "host": "localhost", "port": "1234", "dbName": "testDb", "userName": "user", "sslmode": "verify-ca"}, authParam: map[string]string{"sslrootcert": `/my certs/it's\ca.crt`}, connectionString: `host=localhost port=1234 user=user dbname=testDb sslmode=verify-ca password= sslrootcert='/my certs/it\'s\\ca.crt'`},
 }
 
 func TestPosgresSQLConnectionStringGeneration(t *testing.T) {
@@ -123,6 +131,27 @@ var testPostgresMetadata = []parsePostgresMetadataTestData{
 		resolvedEnv: testPostgresResolvedEnv,
 		raisesError: false,
 	},
+	// Client certificate without key
+	{
+		metadata:    map[string]string{"query": "query", "targetQueryValue": "12"},
+		authParams:  map[string]string{"host": "test_host", "port": "test_port", "userName": "test_username", "password": "POSTGRE_PASSWORD", "dbName": "test_dbname", "sslmode": "require", "sslcert": "/certs/client.crt"},
+		resolvedEnv: testPostgresResolvedEnv,
+		raisesError: true,
+	},
+	// Client certificate with ssl disabled in the connection string
+	{
+		metadata:    map[string]string{"query": "query", "targetQueryValue": "12"},
+		authParams:  map[string]string{"connection": "postgres://user@localhost/testDb?sslmode=disable", "sslcert": "/certs/client.crt", "sslkey": "/certs/client.key"},
+		resolvedEnv: testPostgresResolvedEnv,
+		raisesError: true,
+	},
+	// Client certificate with ssl disabled
+	{
+		metadata:    map[string]string{"query": "query", "targetQueryValue": "12"},
+		authParams:  map[string]string{"host": "test_host", "port": "test_port", "userName": "test_username", "password": "POSTGRE_PASSWORD", "dbName": "test_dbname", "sslmode": "disable", "sslcert": "/certs/client.crt", "sslkey": "/certs/client.key"},
+		resolvedEnv: testPostgresResolvedEnv,
+		raisesError: true,
+	},
 }
 
 func TestParsePosgresSQLMetadata(t *testing.T) {
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	url_pkg "net/url"
 	"strconv"
+	"sync"
 	"time"
 
 	v2beta2 "k8s.io/api/autoscaling/v2beta2"
@@ -27,6 +28,7 @@ const (
 	promQuery         = "query"
 	promThreshold     = "threshold"
 	promNamespace     = "namespace"
+	promCacheWindow   = "cacheWindow"
 )
 
 type prometheusScaler struct {
@@ -41,6 +43,7 @@ type prometheusMetadata struct {
 	threshold      int
 	prometheusAuth *authentication.AuthMeta
 	namespace      string
+	cacheWindow    time.Duration
 	scalerIndex    int
 }
 
@@ -58,6 +61,34 @@ type promQueryResult struct {
 
 var prometheusLog = logf.Log.WithName("prometheus_scaler")
 
+// promQueryCacheKey identifies the queries whose results are shared, the credentials are part of the key
+// so that triggers authenticating differently against the same server never see each other's results
+type promQueryCacheKey struct {
+	serverAddress string
+	namespace     string
+	query         string
+	credentials   string
+}
+
+// promQueryCacheEntry is a cached query result, its lock is held while querying so concurrent callers share one query
+type promQueryCacheEntry struct {
+	lock      sync.Mutex
+	value     float64
+	queriedAt time.Time
+
+	// guarded by the lock of the cache
+	usedAt time.Time
+}
+
+// promQueryCacheExpiry is how long the result of a query no trigger asked for is kept
+const promQueryCacheExpiry = 10 * time.Minute
+
+// promQueryCache shares the results of identical queries across the triggers having a cacheWindow
+var promQueryCache = struct {
+	lock    sync.Mutex
+	entries map[promQueryCacheKey]*promQueryCacheEntry
+}{entries: map[promQueryCacheKey]*promQueryCacheEntry{}}
+
 // NewPrometheusScaler creates a new prometheusScaler
 func NewPrometheusScaler(config *ScalerConfig) (Scaler, error) {
 	meta, err := parsePrometheusMetadata(config)
@@ -118,6 +149,14 @@ func parsePrometheusMetadata(config *ScalerConfig) (meta *prometheusMetadata, er
 		meta.namespace = val
 	}
 
+	if val, ok := config.TriggerMetadata[promCacheWindow]; ok && val != "" {
+		cacheWindow, err := strconv.Atoi(val)
+		if err != nil || cacheWindow < 0 {
+			return nil, fmt.Errorf("invalid %s - must be a non-negative number of seconds", promCacheWindow)
+		}
+		meta.cacheWindow = time.Duration(cacheWindow) * time.Second
+	}
+
 	meta.scalerIndex = config.ScalerIndex
 
 	// parse auth configs from ScalerConfig
@@ -161,7 +200,56 @@ func (s *prometheusScaler) GetMetricSpecForScaling(context.Context) []v2beta2.Me
 	return []v2beta2.MetricSpec{metricSpec}
 }
 
+// ExecutePromQuery returns the result of the query, reusing the result of an identical query if it is fresher than cacheWindow
 func (s *prometheusScaler) ExecutePromQuery(ctx context.Context) (float64, error) {
+	if s.metadata.cacheWindow <= 0 {
+		return s.executePromQuery(ctx)
+	}
+
+	entry := s.getCacheEntry()
+	entry.lock.Lock()
+	defer entry.lock.Unlock()
+	if !entry.queriedAt.IsZero() && time.Since(entry.queriedAt) < s.metadata.cacheWindow {
+		return entry.value, nil
+	}
+
+	v, err := s.executePromQuery(ctx)
+	if err != nil {
+		return -1, err
+	}
+	entry.value = v
+	entry.queriedAt = time.Now()
+	return v, nil
+}
+
+// getCacheEntry returns the cache entry of the query, dropping the entries that were not refreshed for a while
+func (s *prometheusScaler) getCacheEntry() *promQueryCacheEntry {
+	key := promQueryCacheKey{
+		serverAddress: s.metadata.serverAddress,
+		namespace:     s.metadata.namespace,
+		query:         s.metadata.query,
+	}
+	if auth := s.metadata.prometheusAuth; auth != nil {
+		key.credentials = fmt.Sprintf("%s:%s:%s", auth.BearerToken, auth.Username, auth.Password)
+	}
+
+	promQueryCache.lock.Lock()
+	defer promQueryCache.lock.Unlock()
+	for k, entry := range promQueryCache.entries {
+		if time.Since(entry.usedAt) > promQueryCacheExpiry {
+			delete(promQueryCache.entries, k)
+		}
+	}
+	entry, ok := promQueryCache.entries[key]
+	if !ok {
+		entry = &promQueryCacheEntry{}
+		promQueryCache.entries[key] = entry
+	}
+	entry.usedAt = time.Now()
+	return entry
+}
+
+func (s *prometheusScaler) executePromQuery(ctx context.Context) (float64, error) {
 	t := time.Now().UTC().Format(time.RFC3339)
 	queryEscaped := url_pkg.QueryEscape(s.metadata.query)
 	url := fmt.Sprintf("%s/api/v1/query?query=%s&time=%s", s.metadata.serverAddress, queryEscaped, t)
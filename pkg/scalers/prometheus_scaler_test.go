@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -37,6 +38,12 @@ var testPromMetadata = []parsePrometheusMetadataTestData{
 	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "http_requests_total", "threshold": "100", "query": ""}, true},
 	// all properly formed, default disableScaleToZero
 	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "http_requests_total", "threshold": "100", "query": "up"}, false},
+	// all properly formed, with cacheWindow
+	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "http_requests_total", "threshold": "100", "query": "up", "cacheWindow": "30"}, false},
+	// malformed cacheWindow
+	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "http_requests_total", "threshold": "100", "query": "up", "cacheWindow": "30s"}, true},
+	// negative cacheWindow
+	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "http_requests_total", "threshold": "100", "query": "up", "cacheWindow": "-1"}, true},
 }
 
 var prometheusMetricIdentifiers = []prometheusMetricIdentifier{
@@ -208,3 +215,49 @@ func TestPrometheusScalerExecutePromQuery(t *testing.T) {
 		})
 	}
 }
+
+func TestPrometheusScalerQueryCache(t *testing.T) {
+	var queries int
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		queries++
+		if _, err := writer.Write([]byte(`{"data":{"result":[{"value": ["1", "2"]}]}}`)); err != nil {
+			t.Fatal(err)
+		}
+	}))
+	defer server.Close()
+
+	newScaler := func(query string, cacheWindow time.Duration) prometheusScaler {
+		return prometheusScaler{
+			metadata: &prometheusMetadata{
+				serverAddress: server.URL,
+				query:         query,
+				cacheWindow:   cacheWindow,
+			},
+			httpClient: http.DefaultClient,
+		}
+	}
+	first, second := newScaler("up", time.Minute), newScaler("up", time.Minute)
+	other, uncached := newScaler("down", time.Minute), newScaler("up", 0)
+
+	for _, scaler := range []prometheusScaler{first, second, first} {
+		value, err := scaler.ExecutePromQuery(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, float64(2), value)
+	}
+	assert.Equal(t, 1, queries, "identical queries within the cache window are expected to share one query")
+
+	_, err := other.ExecutePromQuery(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, queries, "a different query is expected not to use the cached result")
+
+	_, err = uncached.ExecutePromQuery(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, queries, "a trigger without cacheWindow is expected to always query")
+
+	// the result is queried again once it is older than the cache window
+	expired := newScaler("up", time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	_, err = expired.ExecutePromQuery(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 4, queries, "an expired result is expected to be queried again")
+}
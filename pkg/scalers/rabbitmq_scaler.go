@@ -385,19 +385,24 @@ func getJSON(s *rabbitMQScaler, url string) (queueInfo, error) {
 	defer r.Body.Close()
 
 	if r.StatusCode == 200 {
-		if s.metadata.useRegex {
-			var queues regexQueueInfo
-			err = json.NewDecoder(r.Body).Decode(&queues)
-			if err != nil {
-				return queueInfo{}, err
-			}
-			if queues.TotalPages > 1 {
-				return queueInfo{}, fmt.Errorf("regex matches more queues than can be recovered at once")
-			}
-			result, err := getComposedQueue(s, queues.Queues)
-			return result, err
-		}
+		err = json.NewDecoder(r.Body).Decode(&result)
+		return result, err
+	}
+
+	body, _ := ioutil.ReadAll(r.Body)
+	return result, fmt.Errorf("error requesting rabbitMQ API status: %s, response: %s, from: %s", r.Status, body, url)
+}
+
+// getRegexQueuesPage fetches one page of the queues matching the regex
+func getRegexQueuesPage(s *rabbitMQScaler, url string) (regexQueueInfo, error) {
+	var result regexQueueInfo
+	r, err := s.httpClient.Get(url)
+	if err != nil {
+		return result, err
+	}
+	defer r.Body.Close()
 
+	if r.StatusCode == 200 {
 		err = json.NewDecoder(r.Body).Decode(&result)
 		return result, err
 	}
@@ -406,6 +411,23 @@ func getJSON(s *rabbitMQScaler, url string) (queueInfo, error) {
 	return result, fmt.Errorf("error requesting rabbitMQ API status: %s, response: %s, from: %s", r.Status, body, url)
 }
 
+// getRegexQueues pages through all the queues matching the regex and composes them into a single queue
+func getRegexQueues(s *rabbitMQScaler, managementURL, vhost string) (queueInfo, error) {
+	var queues []queueInfo
+	for page := 1; ; page++ {
+		pageURL := fmt.Sprintf("%s/api/queues%s?page=%d&use_regex=true&pagination=false&name=%s&page_size=%d", managementURL, vhost, page, url.QueryEscape(s.metadata.queueName), s.metadata.pageSize)
+		result, err := getRegexQueuesPage(s, pageURL)
+		if err != nil {
+			return queueInfo{}, err
+		}
+		queues = append(queues, result.Queues...)
+		if page >= result.TotalPages {
+			break
+		}
+	}
+	return getComposedQueue(s, queues)
+}
+
 func (s *rabbitMQScaler) getQueueInfoViaHTTP() (*queueInfo, error) {
 	parsedURL, err := url.Parse(s.metadata.host)
 
@@ -440,16 +462,14 @@ func (s *rabbitMQScaler) getQueueInfoViaHTTP() (*queueInfo, error) {
 	// Clear URL path to get the correct host.
 	parsedURL.Path = ""
 
-	var getQueueInfoManagementURI string
+	var info queueInfo
 	if s.metadata.useRegex {
-		getQueueInfoManagementURI = fmt.Sprintf("%s/api/queues%s?page=1&use_regex=true&pagination=false&name=%s&page_size=%d", parsedURL.String(), vhost, url.QueryEscape(s.metadata.queueName), s.metadata.pageSize)
+		info, err = getRegexQueues(s, parsedURL.String(), vhost)
 	} else {
-		getQueueInfoManagementURI = fmt.Sprintf("%s/api/queues%s/%s", parsedURL.String(), vhost, url.QueryEscape(s.metadata.queueName))
+		getQueueInfoManagementURI := fmt.Sprintf("%s/api/queues%s/%s", parsedURL.String(), vhost, url.QueryEscape(s.metadata.queueName))
+		info, err = getJSON(s, getQueueInfoManagementURI)
 	}
 
-	var info queueInfo
-	info, err = getJSON(s, getQueueInfoManagementURI)
-
 	if err != nil {
 		return nil, err
 	}
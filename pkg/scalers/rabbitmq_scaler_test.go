@@ -533,28 +533,41 @@ func TestRabbitMQAnonimizeRabbitMQError(t *testing.T) {
 }
 
 type getQueueInfoNavigationTestData struct {
-	response string
-	isError  bool
+	pages    []string
+	messages int64
 }
 
 var testRegexQueueInfoNavigationTestData = []getQueueInfoNavigationTestData{
-	// sum queue length
-	{`{"items":[], "filtered_count": 250, "page": 1, "page_count": 3}`, true},
-	{`{"items":[], "filtered_count": 250, "page": 1, "page_count": 1}`, false},
+	// single page
+	{[]string{`{"items":[{"messages": 4}, {"messages": 3}], "filtered_count": 2, "page": 1, "page_count": 1}`}, 7},
+	// queues are summed across all the pages
+	{[]string{
+		`{"items":[{"messages": 4}, {"messages": 3}], "filtered_count": 5, "page": 1, "page_count": 3}`,
+		`{"items":[{"messages": 2}, {"messages": 1}], "filtered_count": 5, "page": 2, "page_count": 3}`,
+		`{"items":[{"messages": 5}], "filtered_count": 5, "page": 3, "page_count": 3}`,
+	}, 15},
+	// no queue matches
+	{[]string{`{"items":[], "filtered_count": 0, "page": 1, "page_count": 0}`}, 0},
 }
 
-func TestRegexQueueMissingError(t *testing.T) {
+func TestRegexQueuePagination(t *testing.T) {
 	for _, testData := range testRegexQueueInfoNavigationTestData {
+		var requests int
 		var apiStub = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			expectedPath := "/api/queues?page=1&use_regex=true&pagination=false&name=evaluate_trials&page_size=100"
+			requests++
+			expectedPath := fmt.Sprintf("/api/queues?page=%d&use_regex=true&pagination=false&name=evaluate_trials&page_size=100", requests)
 			if r.RequestURI != expectedPath {
 				t.Error("Expect request path to =", expectedPath, "but it is", r.RequestURI)
 			}
+			if requests > len(testData.pages) {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
 
 			w.WriteHeader(http.StatusOK)
-			_, err := w.Write([]byte(testData.response))
+			_, err := w.Write([]byte(testData.pages[requests-1]))
 			if err != nil {
-				t.Error("Expect request path to =", testData.response, "but it is", err)
+				t.Error("Expect request path to =", testData.pages[requests-1], "but it is", err)
 			}
 		}))
 
@@ -580,13 +593,15 @@ func TestRegexQueueMissingError(t *testing.T) {
 			t.Error("Expect success", err)
 		}
 
-		ctx := context.TODO()
-		_, err = s.IsActive(ctx)
-		if err != nil && !testData.isError {
+		metrics, err := s.GetMetrics(context.TODO(), "", nil)
+		if err != nil {
 			t.Error("Expected success but got error", err)
+		} else if metrics[0].Value.Value() != testData.messages {
+			t.Error("Expected", testData.messages, "messages but got", metrics[0].Value.Value())
 		}
-		if testData.isError && err == nil {
-			t.Error("Expected error but got success")
+		if requests != len(testData.pages) {
+			t.Error("Expected", len(testData.pages), "requests but got", requests)
 		}
+		apiStub.Close()
 	}
 }
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
+	"github.com/go-redis/redis/v8"
 	v2beta2 "k8s.io/api/autoscaling/v2beta2"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -19,15 +21,18 @@ const (
 	// defaults
 	defaultTargetPendingEntriesCount = 5
 	defaultDBIndex                   = 0
+	// number of pending entries read per XPENDING call when counting the idle ones
+	pendingEntriesPageSize = 1000
 
 	// metadata names
-	pendingEntriesCountMetadata = "pendingEntriesCount"
-	streamNameMetadata          = "stream"
-	consumerGroupNameMetadata   = "consumerGroup"
-	usernameMetadata            = "username"
-	passwordMetadata            = "password"
-	databaseIndexMetadata       = "databaseIndex"
-	enableTLSMetadata           = "enableTLS"
+	pendingEntriesCountMetadata       = "pendingEntriesCount"
+	streamNameMetadata                = "stream"
+	consumerGroupNameMetadata         = "consumerGroup"
+	usernameMetadata                  = "username"
+	passwordMetadata                  = "password"
+	databaseIndexMetadata             = "databaseIndex"
+	enableTLSMetadata                 = "enableTLS"
+	pendingEntriesMinIdleTimeMetadata = "pendingEntriesMinIdleTime"
 )
 
 type redisStreamsScaler struct {
@@ -40,6 +45,8 @@ type redisStreamsMetadata struct {
 	targetPendingEntriesCount int
 	streamName                string
 	consumerGroupName         string
+	// only the pending entries idle for at least this long are counted when set
+	pendingEntriesMinIdleTime time.Duration
 	databaseIndex             int
 	connectionInfo            redisConnectionInfo
 	scalerIndex               int
@@ -84,11 +91,7 @@ func createClusteredRedisStreamsScaler(ctx context.Context, meta *redisStreamsMe
 	}
 
 	pendingEntriesCountFn := func(ctx context.Context) (int64, error) {
-		pendingEntries, err := client.XPending(ctx, meta.streamName, meta.consumerGroupName).Result()
-		if err != nil {
-			return -1, err
-		}
-		return pendingEntries.Count, nil
+		return getRedisStreamsPendingEntriesCount(ctx, client, meta)
 	}
 
 	return &redisStreamsScaler{
@@ -113,11 +116,7 @@ func createSentinelRedisStreamsScaler(ctx context.Context, meta *redisStreamsMet
 	}
 
 	pendingEntriesCountFn := func(ctx context.Context) (int64, error) {
-		pendingEntries, err := client.XPending(ctx, meta.streamName, meta.consumerGroupName).Result()
-		if err != nil {
-			return -1, err
-		}
-		return pendingEntries.Count, nil
+		return getRedisStreamsPendingEntriesCount(ctx, client, meta)
 	}
 
 	return &redisStreamsScaler{
@@ -142,11 +141,7 @@ func createRedisStreamsScaler(ctx context.Context, meta *redisStreamsMetadata) (
 	}
 
 	pendingEntriesCountFn := func(ctx context.Context) (int64, error) {
-		pendingEntries, err := client.XPending(ctx, meta.streamName, meta.consumerGroupName).Result()
-		if err != nil {
-			return -1, err
-		}
-		return pendingEntries.Count, nil
+		return getRedisStreamsPendingEntriesCount(ctx, client, meta)
 	}
 
 	return &redisStreamsScaler{
@@ -156,6 +151,47 @@ func createRedisStreamsScaler(ctx context.Context, meta *redisStreamsMetadata) (
 	}, nil
 }
 
+// redisStreamsPendingClient is the part of the redis clients the scaler reads the pending entries with
+type redisStreamsPendingClient interface {
+	XPending(ctx context.Context, stream, group string) *redis.XPendingCmd
+	XPendingExt(ctx context.Context, a *redis.XPendingExtArgs) *redis.XPendingExtCmd
+}
+
+// getRedisStreamsPendingEntriesCount returns the number of entries of the consumer group's 'Pending Entries List',
+// or only of those idle for at least pendingEntriesMinIdleTime when it is set, which requires Redis 6.2
+func getRedisStreamsPendingEntriesCount(ctx context.Context, client redisStreamsPendingClient, meta *redisStreamsMetadata) (int64, error) {
+	if meta.pendingEntriesMinIdleTime <= 0 {
+		pendingEntries, err := client.XPending(ctx, meta.streamName, meta.consumerGroupName).Result()
+		if err != nil {
+			return -1, err
+		}
+		return pendingEntries.Count, nil
+	}
+
+	// the idle entries can't be counted by the XPENDING summary, they are listed page by page
+	var count int64
+	start := "-"
+	for {
+		pendingEntries, err := client.XPendingExt(ctx, &redis.XPendingExtArgs{
+			Stream: meta.streamName,
+			Group:  meta.consumerGroupName,
+			Idle:   meta.pendingEntriesMinIdleTime,
+			Start:  start,
+			End:    "+",
+			Count:  pendingEntriesPageSize,
+		}).Result()
+		if err != nil {
+			return -1, err
+		}
+		count += int64(len(pendingEntries))
+		if len(pendingEntries) < pendingEntriesPageSize {
+			return count, nil
+		}
+		// continue after the last entry, exclusive ranges require Redis 6.2 like the IDLE filter
+		start = "(" + pendingEntries[len(pendingEntries)-1].ID
+	}
+}
+
 func parseRedisStreamsMetadata(config *ScalerConfig, parseFn redisAddressParser) (*redisStreamsMetadata, error) {
 	connInfo, err := parseFn(config.TriggerMetadata, config.ResolvedEnv, config.AuthParams)
 	if err != nil {
@@ -188,6 +224,14 @@ func parseRedisStreamsMetadata(config *ScalerConfig, parseFn redisAddressParser)
 		return nil, fmt.Errorf("missing redis stream consumer group name")
 	}
 
+	if val, ok := config.TriggerMetadata[pendingEntriesMinIdleTimeMetadata]; ok {
+		minIdleTime, err := strconv.Atoi(val)
+		if err != nil || minIdleTime <= 0 {
+			return nil, fmt.Errorf("invalid %s - must be a positive number of milliseconds", pendingEntriesMinIdleTimeMetadata)
+		}
+		meta.pendingEntriesMinIdleTime = time.Duration(minIdleTime) * time.Millisecond
+	}
+
 	meta.databaseIndex = defaultDBIndex
 	if val, ok := config.TriggerMetadata[databaseIndexMetadata]; ok {
 		dbIndex, err := strconv.ParseInt(val, 10, 32)
@@ -3,9 +3,13 @@ package scalers
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/go-redis/redis/v8"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -834,3 +838,79 @@ func TestParseRedisSentinelStreamsMetadata(t *testing.T) {
 		})
 	}
 }
+
+func TestParseRedisStreamsPendingEntriesMinIdleTime(t *testing.T) {
+	cases := []struct {
+		name        string
+		minIdleTime string
+		expected    time.Duration
+		isError     bool
+	}{
+		{"milliseconds", "60000", time.Minute, false},
+		{"zero", "0", 0, true},
+		{"not a number", "1m", 0, true},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			metadata := map[string]string{"stream": "my-stream", "consumerGroup": "my-stream-consumer-group", "pendingEntriesCount": "5", "address": "REDIS_SERVER", pendingEntriesMinIdleTimeMetadata: tc.minIdleTime}
+			m, err := parseRedisStreamsMetadata(&ScalerConfig{TriggerMetadata: metadata, ResolvedEnv: map[string]string{"REDIS_SERVER": "myredis:6379"}}, parseRedisAddress)
+			if tc.isError {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, m.pendingEntriesMinIdleTime)
+		})
+	}
+}
+
+// fakeRedisStreamsPendingClient serves the pending entries with the given IDs and a summary count of 7
+type fakeRedisStreamsPendingClient struct {
+	ids  []string
+	args []redis.XPendingExtArgs
+}
+
+func (c *fakeRedisStreamsPendingClient) XPending(ctx context.Context, stream, group string) *redis.XPendingCmd {
+	cmd := redis.NewXPendingCmd(ctx)
+	cmd.SetVal(&redis.XPending{Count: 7})
+	return cmd
+}
+
+func (c *fakeRedisStreamsPendingClient) XPendingExt(ctx context.Context, a *redis.XPendingExtArgs) *redis.XPendingExtCmd {
+	c.args = append(c.args, *a)
+	var entries []redis.XPendingExt
+	for _, id := range c.ids {
+		if (a.Start == "-" || id > strings.TrimPrefix(a.Start, "(")) && int64(len(entries)) < a.Count {
+			entries = append(entries, redis.XPendingExt{ID: id})
+		}
+	}
+	cmd := redis.NewXPendingExtCmd(ctx)
+	cmd.SetVal(entries)
+	return cmd
+}
+
+func TestRedisStreamsPendingEntriesCount(t *testing.T) {
+	ids := make([]string, 0, 2500)
+	for i := 0; i < 2500; i++ {
+		ids = append(ids, fmt.Sprintf("1640000000000-%04d", i))
+	}
+
+	// the summary count is used by default
+	client := &fakeRedisStreamsPendingClient{ids: ids}
+	count, err := getRedisStreamsPendingEntriesCount(context.Background(), client, &redisStreamsMetadata{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(7), count)
+	assert.Empty(t, client.args)
+
+	// the idle entries are listed page by page
+	count, err = getRedisStreamsPendingEntriesCount(context.Background(), client, &redisStreamsMetadata{streamName: "my-stream", consumerGroupName: "my-group", pendingEntriesMinIdleTime: time.Minute})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2500), count)
+	assert.Len(t, client.args, 3)
+	assert.Equal(t, time.Minute, client.args[0].Idle)
+	assert.Equal(t, "-", client.args[0].Start)
+	assert.Equal(t, "(1640000000000-0999", client.args[1].Start)
+	assert.Equal(t, "(1640000000000-1999", client.args[2].Start)
+}
@@ -19,6 +19,9 @@ package scalers
 import (
 	"context"
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
 	"time"
 
 	"k8s.io/api/autoscaling/v2beta2"
@@ -59,6 +62,62 @@ type PushScaler interface {
 	Run(ctx context.Context, active chan<- bool)
 }
 
+// HealthCheckScaler interface is implemented by the scalers able to check that their backend is reachable
+type HealthCheckScaler interface {
+	Scaler
+
+	// HealthCheck returns an error if the backend can't be reached with the scaler's configuration
+	HealthCheck(ctx context.Context) error
+}
+
+// HealthCheck checks the backend of the scaler, scalers not implementing HealthCheckScaler are reported healthy
+func HealthCheck(ctx context.Context, scaler Scaler) error {
+	if healthCheckScaler, ok := scaler.(HealthCheckScaler); ok {
+		return healthCheckScaler.HealthCheck(ctx)
+	}
+	return nil
+}
+
+// LastMetricValueScaler interface is implemented by the scalers keeping the last value read from their backend,
+// so that it can be reported in the status without waiting for the HPA to ask for the metric
+type LastMetricValueScaler interface {
+	Scaler
+
+	// LastMetricValue returns the last value read from the backend, false when no value has been read yet
+	LastMetricValue() (float64, bool)
+}
+
+// LastMetricValue returns the last value read by the scaler, false when it has none or doesn't implement LastMetricValueScaler
+func LastMetricValue(scaler Scaler) (float64, bool) {
+	if lastMetricValueScaler, ok := scaler.(LastMetricValueScaler); ok {
+		return lastMetricValueScaler.LastMetricValue()
+	}
+	return 0, false
+}
+
+// MetricsAndActivityScaler interface is implemented by the scalers able to read their metrics and activity from a
+// single poll of their backend, rather than one for IsActive and another for GetMetrics
+type MetricsAndActivityScaler interface {
+	Scaler
+
+	// GetMetricsAndActivity returns the metric values for the metric name and whether the scaler is active
+	GetMetricsAndActivity(ctx context.Context, metricName string) ([]external_metrics.ExternalMetricValue, bool, error)
+}
+
+// GetMetricsAndActivity returns the metric values and the activity of the scaler, with a single call for the scalers
+// implementing MetricsAndActivityScaler and with IsActive then GetMetrics for the others
+func GetMetricsAndActivity(ctx context.Context, scaler Scaler, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, bool, error) {
+	if metricsAndActivityScaler, ok := scaler.(MetricsAndActivityScaler); ok {
+		return metricsAndActivityScaler.GetMetricsAndActivity(ctx, metricName)
+	}
+	isActive, err := scaler.IsActive(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	metrics, err := scaler.GetMetrics(ctx, metricName, metricSelector)
+	return metrics, isActive, err
+}
+
 // ScalerConfig contains config fields common for all scalers
 type ScalerConfig struct {
 	// Name used for external scalers
@@ -101,6 +160,25 @@ func GetFromAuthOrMeta(config *ScalerConfig, field string) (string, error) {
 	return result, err
 }
 
+// GetActivationThreshold parses the activation threshold set in the trigger metadata under key,
+// the scaler is only active when its metric is above the threshold
+func GetActivationThreshold(config *ScalerConfig, key string, defaultValue float64) (float64, error) {
+	val, ok := config.TriggerMetadata[key]
+	if !ok || strings.TrimSpace(val) == "" {
+		return defaultValue, nil
+	}
+	threshold, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+	if err != nil || threshold < 0 || math.IsInf(threshold, 0) || math.IsNaN(threshold) {
+		return 0, fmt.Errorf("invalid %s - must be a non-negative number", key)
+	}
+	return threshold, nil
+}
+
+// IsAboveActivationThreshold helps deciding whether a scaler is active for its metric value
+func IsAboveActivationThreshold(value, threshold float64) bool {
+	return value > threshold
+}
+
 // GenerateMetricNameWithIndex helps adding the index prefix to the metric name
 func GenerateMetricNameWithIndex(scalerIndex int, metricName string) string {
 	return fmt.Sprintf("s%d-%s", scalerIndex, metricName)
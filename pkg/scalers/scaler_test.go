@@ -0,0 +1,57 @@
+package scalers
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGetActivationThreshold(t *testing.T) {
+	testCases := []struct {
+		name      string
+		metadata  map[string]string
+		threshold float64
+		isError   bool
+	}{
+		{"default", map[string]string{}, 2, false},
+		{"empty value uses the default", map[string]string{"activationTarget": ""}, 2, false},
+		{"integer", map[string]string{"activationTarget": "5"}, 5, false},
+		{"decimal", map[string]string{"activationTarget": " 0.5 "}, 0.5, false},
+		{"zero", map[string]string{"activationTarget": "0"}, 0, false},
+		{"negative", map[string]string{"activationTarget": "-1"}, 0, true},
+		{"not a number", map[string]string{"activationTarget": "AA"}, 0, true},
+		{"infinite", map[string]string{"activationTarget": "Inf"}, 0, true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			threshold, err := GetActivationThreshold(&ScalerConfig{TriggerMetadata: testCase.metadata}, "activationTarget", 2)
+			if testCase.isError {
+				if err == nil {
+					t.Error("Expected error but got success")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("Expected success but got error", err)
+			}
+			if threshold != testCase.threshold {
+				t.Errorf("Wrong threshold: %g, expected: %g", threshold, testCase.threshold)
+			}
+		})
+	}
+}
+
+func TestIsAboveActivationThreshold(t *testing.T) {
+	if IsAboveActivationThreshold(5, 5) {
+		t.Error("Expected a value at the threshold not to be active")
+	}
+	if !IsAboveActivationThreshold(5.5, 5) {
+		t.Error("Expected a value above the threshold to be active")
+	}
+}
+
+func TestHealthCheckWithoutHealthCheckScaler(t *testing.T) {
+	if err := HealthCheck(context.Background(), &prometheusScaler{}); err != nil {
+		t.Error("Expected scalers not implementing HealthCheckScaler to be healthy but got error", err)
+	}
+}
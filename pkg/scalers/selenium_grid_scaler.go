@@ -31,6 +31,7 @@ type seleniumGridScalerMetadata struct {
 	browserName    string
 	targetValue    int64
 	browserVersion string
+	platformName   string
 	unsafeSsl      bool
 	scalerIndex    int
 }
@@ -57,10 +58,13 @@ type seleniumSession struct {
 type capability struct {
 	BrowserName    string `json:"browserName"`
 	BrowserVersion string `json:"browserVersion"`
+	PlatformName   string `json:"platformName"`
 }
 
 const (
 	DefaultBrowserVersion string = "latest"
+	// anyPlatformName is the platformName of a request that can run on any platform
+	anyPlatformName string = "any"
 )
 
 var seleniumGridLog = logf.Log.WithName("selenium_grid_scaler")
@@ -103,6 +107,10 @@ func parseSeleniumGridScalerMetadata(config *ScalerConfig) (*seleniumGridScalerM
 		meta.browserVersion = DefaultBrowserVersion
 	}
 
+	if val, ok := config.TriggerMetadata["platformName"]; ok && val != "" {
+		meta.platformName = val
+	}
+
 	if val, ok := config.TriggerMetadata["unsafeSsl"]; ok {
 		parsedVal, err := strconv.ParseBool(val)
 		if err != nil {
@@ -191,14 +199,23 @@ func (s *seleniumGridScaler) getSessionsCount(ctx context.Context) (*resource.Qu
 	if err != nil {
 		return nil, err
 	}
-	v, err := getCountFromSeleniumResponse(b, s.metadata.browserName, s.metadata.browserVersion)
+	v, err := getCountFromSeleniumResponse(b, s.metadata.browserName, s.metadata.browserVersion, s.metadata.platformName)
 	if err != nil {
 		return nil, err
 	}
 	return v, nil
 }
 
-func getCountFromSeleniumResponse(b []byte, browserName string, browserVersion string) (*resource.Quantity, error) {
+// platformNameMatches tells whether a request or session for the platform is counted by a scaler of platformName.
+// Without platformName every platform is counted, requests that don't ask for a platform can run on any of them.
+func platformNameMatches(platform string, platformName string) bool {
+	if platformName == "" || platform == "" || strings.EqualFold(platform, anyPlatformName) {
+		return true
+	}
+	return strings.EqualFold(platform, platformName)
+}
+
+func getCountFromSeleniumResponse(b []byte, browserName string, browserVersion string, platformName string) (*resource.Quantity, error) {
 	var count int64
 	var seleniumResponse = seleniumResponse{}
 
@@ -210,7 +227,7 @@ func getCountFromSeleniumResponse(b []byte, browserName string, browserVersion s
 	for _, sessionQueueRequest := range sessionQueueRequests {
 		var capability = capability{}
 		if err := json.Unmarshal([]byte(sessionQueueRequest), &capability); err == nil {
-			if capability.BrowserName == browserName {
+			if capability.BrowserName == browserName && platformNameMatches(capability.PlatformName, platformName) {
 				if strings.HasPrefix(capability.BrowserVersion, browserVersion) {
 					count++
 				} else if capability.BrowserVersion == "" && browserVersion == DefaultBrowserVersion {
@@ -226,7 +243,7 @@ func getCountFromSeleniumResponse(b []byte, browserName string, browserVersion s
 	for _, session := range sessions {
 		var capability = capability{}
 		if err := json.Unmarshal([]byte(session.Capabilities), &capability); err == nil {
-			if capability.BrowserName == browserName {
+			if capability.BrowserName == browserName && platformNameMatches(capability.PlatformName, platformName) {
 				if strings.HasPrefix(capability.BrowserVersion, browserVersion) {
 					count++
 				} else if browserVersion == DefaultBrowserVersion {
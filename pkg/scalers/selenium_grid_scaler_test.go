@@ -12,6 +12,7 @@ func Test_getCountFromSeleniumResponse(t *testing.T) {
 		b              []byte
 		browserName    string
 		browserVersion string
+		platformName   string
 	}
 	tests := []struct {
 		name    string
@@ -139,10 +140,39 @@ func Test_getCountFromSeleniumResponse(t *testing.T) {
 			want:    resource.NewQuantity(2, resource.DecimalSI),
 			wantErr: false,
 		},
+		{
+			name: "active sessions with matching browsername and platform should return count as 3",
+			args: args{
+				b: []byte(`{
+					"data": {
+						"sessionsInfo": {
+							"sessionQueueRequests": ["{\n  \"browserName\": \"chrome\",\n \"platformName\": \"Windows 11\"\n}","{\n  \"browserName\": \"chrome\",\n \"platformName\": \"linux\"\n}","{\n  \"browserName\": \"chrome\",\n \"platformName\": \"ANY\"\n}","{\n  \"browserName\": \"chrome\"\n}"],
+							"sessions": [
+								{
+									"id": "0f9c5a941aa4d755a54b84be1f6535b1",
+									"capabilities": "{\n  \"browserName\": \"chrome\",\n  \"browserVersion\": \"91.0.4472.114\",\n  \"platformName\": \"linux\"\n}",
+									"nodeId": "d44dcbc5-0b2c-4d5e-abf4-6f6aa5e0983c"
+								},
+								{
+									"id": "a2b8c6e05fd34c2b8e3b5d1e8a8b7c11",
+									"capabilities": "{\n  \"browserName\": \"chrome\",\n  \"browserVersion\": \"91.0.4472.114\",\n  \"platformName\": \"WINDOWS\"\n}",
+									"nodeId": "4d1c7a3e-9a5b-4f2e-8e7b-2c9d0f6b1a33"
+								}
+							]
+						}
+					}
+				}`),
+				browserName:    "chrome",
+				browserVersion: "latest",
+				platformName:   "Windows 11",
+			},
+			want:    resource.NewQuantity(3, resource.DecimalSI),
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := getCountFromSeleniumResponse(tt.args.b, tt.args.browserName, tt.args.browserVersion)
+			got, err := getCountFromSeleniumResponse(tt.args.b, tt.args.browserName, tt.args.browserVersion, tt.args.platformName)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("getCountFromSeleniumResponse() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -244,6 +274,26 @@ func Test_parseSeleniumGridScalerMetadata(t *testing.T) {
 				unsafeSsl:      true,
 			},
 		},
+		{
+			name: "valid url, browsername and platformName should return metadata",
+			args: args{
+				config: &ScalerConfig{
+					TriggerMetadata: map[string]string{
+						"url":          "http://selenium-hub:4444/graphql",
+						"browserName":  "chrome",
+						"platformName": "linux",
+					},
+				},
+			},
+			wantErr: false,
+			want: &seleniumGridScalerMetadata{
+				url:            "http://selenium-hub:4444/graphql",
+				browserName:    "chrome",
+				targetValue:    1,
+				browserVersion: "latest",
+				platformName:   "linux",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -23,6 +23,7 @@ const (
 	solaceScalerID      = "solace"
 	// REST ENDPOINT String Patterns
 	solaceSempEndpointURLTemplate = "%s/%s/%s/monitor/msgVpns/%s/%ss/%s"
+	solaceSempVpnURLTemplate      = "%s/%s/%s/monitor/msgVpns/%s"
 	// SEMP REST API Context
 	solaceAPIName            = "SEMP"
 	solaceAPIVersion         = "v2"
@@ -43,9 +44,11 @@ const (
 	// Metric Targets
 	solaceMetaMsgCountTarget      = "messageCountTarget"
 	solaceMetaMsgSpoolUsageTarget = "messageSpoolUsageTarget"
+	solaceMetaSpoolUsageTarget    = "spoolUsageTarget"
 	// Trigger type identifiers
 	solaceTriggermsgcount      = "msgcount"
 	solaceTriggermsgspoolusage = "msgspoolusage"
+	solaceTriggervpnspoolusage = "vpnspoolusage"
 )
 
 // Struct for Observed Metric Values
@@ -63,8 +66,10 @@ type SolaceScaler struct {
 
 type SolaceMetadata struct {
 	// Full SEMP URL to target queue (CONSTRUCTED IN CODE)
-	endpointURL   string
-	solaceSempURL string
+	endpointURL string
+	// Full SEMP URL to the Message VPN (CONSTRUCTED IN CODE)
+	vpnEndpointURL string
+	solaceSempURL  string
 	// Solace Message VPN
 	messageVpn string
 	queueName  string
@@ -75,6 +80,7 @@ type SolaceMetadata struct {
 	// Target Message Count
 	msgCountTarget      int
 	msgSpoolUsageTarget int // Spool Use Target in Megabytes
+	spoolUsageTarget    int // Message VPN Spool Use Target in percent of the VPN spool quota
 	// Scaler index
 	scalerIndex int
 }
@@ -91,9 +97,12 @@ type solaceSEMPCollections struct {
 	Msgs solaceSEMPMessages `json:"msgs"`
 }
 
-// SEMP API Response Queue Data Struct
+// SEMP API Response Queue and Message VPN Data Struct
 type solaceSEMPData struct {
-	MsgSpoolUsage int `json:"msgSpoolUsage"`
+	// Spool usage in bytes
+	MsgSpoolUsage int64 `json:"msgSpoolUsage"`
+	// Spool quota of the Message VPN in megabytes, only returned for a Message VPN
+	MaxMsgSpoolUsage int64 `json:"maxMsgSpoolUsage"`
 }
 
 // SEMP API Messages Struct
@@ -167,8 +176,20 @@ func parseSolaceMetadata(config *ScalerConfig) (*SolaceMetadata, error) {
 		}
 	}
 
+	//	GET spoolUsageTarget
+	if val, ok := config.TriggerMetadata[solaceMetaSpoolUsageTarget]; ok && val != "" {
+		spoolUsage, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("can't parse [%s], not a valid integer: %s", solaceMetaSpoolUsageTarget, err)
+		}
+		if spoolUsage < 1 || spoolUsage > 100 {
+			return nil, fmt.Errorf("[%s] must be a percentage between 1 and 100", solaceMetaSpoolUsageTarget)
+		}
+		meta.spoolUsageTarget = spoolUsage
+	}
+
 	//	Check that we have at least one positive target value for the scaler
-	if meta.msgCountTarget < 1 && meta.msgSpoolUsageTarget < 1 {
+	if meta.msgCountTarget < 1 && meta.msgSpoolUsageTarget < 1 && meta.spoolUsageTarget < 1 {
 		return nil, fmt.Errorf("no target value found in the scaler configuration")
 	}
 
@@ -182,6 +203,14 @@ func parseSolaceMetadata(config *ScalerConfig) (*SolaceMetadata, error) {
 		solaceAPIObjectTypeQueue,
 		meta.queueName)
 
+	// Format Solace SEMP Message VPN Endpoint (REST URL)
+	meta.vpnEndpointURL = fmt.Sprintf(
+		solaceSempVpnURLTemplate,
+		meta.solaceSempURL,
+		solaceAPIName,
+		solaceAPIVersion,
+		meta.messageVpn)
+
 	// Get Credentials
 	var e error
 	if meta.username, meta.password, e = getSolaceSempCredentials(config); e != nil {
@@ -236,8 +265,10 @@ func getSolaceSempCredentials(config *ScalerConfig) (u string, p string, err err
 //	CURRENT SUPPORTED METRICS ARE:
 //	- QUEUE MESSAGE COUNT (msgCount)
 //	- QUEUE SPOOL USAGE   (msgSpoolUsage in MBytes)
+//	- VPN SPOOL USAGE     (vpnSpoolUsage in percent of the VPN spool quota)
 //	METRIC IDENTIFIER HAS THE SIGNATURE:
 //	- solace-[Queue_Name]-[metric_type]
+//	- solace-[Message_VPN]-[metric_type] for the VPN spool usage
 //	e.g. solace-QUEUE1-msgCount
 func (s *SolaceScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
 	var metricSpecList []v2beta2.MetricSpec
@@ -273,21 +304,62 @@ func (s *SolaceScaler) GetMetricSpecForScaling(context.Context) []v2beta2.Metric
 		metricSpec := v2beta2.MetricSpec{External: externalMetric, Type: solaceExtMetricType}
 		metricSpecList = append(metricSpecList, metricSpec)
 	}
+	// Message VPN Spool Usage Target Spec
+	if s.metadata.spoolUsageTarget > 0 {
+		targetMetricValue := resource.NewQuantity(int64(s.metadata.spoolUsageTarget), resource.DecimalSI)
+		metricName := kedautil.NormalizeString(fmt.Sprintf("solace-%s-%s", s.metadata.messageVpn, solaceTriggervpnspoolusage))
+		// the spool usage of the Message VPN is shared by all the replicas, it is not averaged over them
+		externalMetric := &v2beta2.ExternalMetricSource{
+			Metric: v2beta2.MetricIdentifier{
+				Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, metricName),
+			},
+			Target: v2beta2.MetricTarget{
+				Type:  v2beta2.ValueMetricType,
+				Value: targetMetricValue,
+			},
+		}
+		metricSpec := v2beta2.MetricSpec{External: externalMetric, Type: solaceExtMetricType}
+		metricSpecList = append(metricSpecList, metricSpec)
+	}
 	return metricSpecList
 }
 
 //	returns SolaceMetricValues struct populated from broker  SEMP endpoint
 func (s *SolaceScaler) getSolaceQueueMetricsFromSEMP(ctx context.Context) (SolaceMetricValues, error) {
-	var scaledMetricEndpointURL = s.metadata.endpointURL
-	var httpClient = s.httpClient
-	var sempResponse solaceSEMPResponse
 	var metricValues SolaceMetricValues
 
+	sempResponse, err := s.getSEMPResponse(ctx, s.metadata.endpointURL)
+	if err != nil {
+		return SolaceMetricValues{}, err
+	}
+
+	// Set Return Values
+	metricValues.msgCount = sempResponse.Collections.Msgs.Count
+	metricValues.msgSpoolUsage = int(sempResponse.Data.MsgSpoolUsage)
+	return metricValues, nil
+}
+
+//	returns the spool usage of the Message VPN in percent of its spool quota
+func (s *SolaceScaler) getSolaceVpnSpoolUsageFromSEMP(ctx context.Context) (float64, error) {
+	sempResponse, err := s.getSEMPResponse(ctx, s.metadata.vpnEndpointURL)
+	if err != nil {
+		return 0, err
+	}
+	if sempResponse.Data.MaxMsgSpoolUsage <= 0 {
+		return 0, fmt.Errorf("message vpn %s has no spool quota", s.metadata.messageVpn)
+	}
+	return float64(sempResponse.Data.MsgSpoolUsage) * 100 / float64(sempResponse.Data.MaxMsgSpoolUsage*1024*1024), nil
+}
+
+//	calls the SEMP API at the URL and returns the decoded response
+func (s *SolaceScaler) getSEMPResponse(ctx context.Context, url string) (*solaceSEMPResponse, error) {
+	var sempResponse solaceSEMPResponse
+
 	//	RETRIEVE METRICS FROM SOLACE SEMP API
 	//	Define HTTP Request
-	request, err := http.NewRequestWithContext(ctx, "GET", scaledMetricEndpointURL, nil)
+	request, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return SolaceMetricValues{}, fmt.Errorf("failed attempting request to solace semp api: %s", err)
+		return nil, fmt.Errorf("failed attempting request to solace semp api: %s", err)
 	}
 
 	//	Add HTTP Auth and Headers
@@ -295,36 +367,47 @@ func (s *SolaceScaler) getSolaceQueueMetricsFromSEMP(ctx context.Context) (Solac
 	request.Header.Set("Content-Type", "application/json")
 
 	//	Call Solace SEMP API
-	response, err := httpClient.Do(request)
+	response, err := s.httpClient.Do(request)
 	if err != nil {
-		return SolaceMetricValues{}, fmt.Errorf("call to solace semp api failed: %s", err)
+		return nil, fmt.Errorf("call to solace semp api failed: %s", err)
 	}
 	defer response.Body.Close()
 
 	// Check HTTP Status Code
 	if response.StatusCode < 200 || response.StatusCode > 299 {
 		sempError := fmt.Errorf("semp request http status code: %s - %s", strconv.Itoa(response.StatusCode), response.Status)
-		return SolaceMetricValues{}, sempError
+		return nil, sempError
 	}
 
 	// Decode SEMP Response and Test
 	if err := json.NewDecoder(response.Body).Decode(&sempResponse); err != nil {
-		return SolaceMetricValues{}, fmt.Errorf("failed to read semp response body: %s", err)
+		return nil, fmt.Errorf("failed to read semp response body: %s", err)
 	}
 	if sempResponse.Meta.ResponseCode < 200 || sempResponse.Meta.ResponseCode > 299 {
-		return SolaceMetricValues{}, fmt.Errorf("solace semp api returned error status: %d", sempResponse.Meta.ResponseCode)
+		return nil, fmt.Errorf("solace semp api returned error status: %d", sempResponse.Meta.ResponseCode)
 	}
-
-	// Set Return Values
-	metricValues.msgCount = sempResponse.Collections.Msgs.Count
-	metricValues.msgSpoolUsage = sempResponse.Data.MsgSpoolUsage
-	return metricValues, nil
+	return &sempResponse, nil
 }
 
 //	INTERFACE METHOD
 //	Call SEMP API to retrieve metrics
 //	returns value for named metric
 func (s *SolaceScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	//	The Message VPN spool usage is read from the Message VPN, not the queue
+	if strings.HasSuffix(metricName, solaceTriggervpnspoolusage) {
+		spoolUsage, err := s.getSolaceVpnSpoolUsageFromSEMP(ctx)
+		if err != nil {
+			solaceLog.Error(err, "call to semp endpoint failed")
+			return []external_metrics.ExternalMetricValue{}, err
+		}
+		metric := external_metrics.ExternalMetricValue{
+			MetricName: metricName,
+			Value:      *resource.NewMilliQuantity(int64(spoolUsage*1000), resource.DecimalSI),
+			Timestamp:  metav1.Now(),
+		}
+		return append([]external_metrics.ExternalMetricValue{}, metric), nil
+	}
+
 	var metricValues, mv SolaceMetricValues
 	var mve error
 	if mv, mve = s.getSolaceQueueMetricsFromSEMP(ctx); mve != nil {
@@ -359,6 +442,7 @@ func (s *SolaceScaler) GetMetrics(ctx context.Context, metricName string, metric
 //	INTERFACE METHOD
 //	Call SEMP API to retrieve metrics
 //	IsActive returns true if queue messageCount > 0 || msgSpoolUsage > 0
+//	The Message VPN spool usage is shared with other queues, it does not activate the scaler
 func (s *SolaceScaler) IsActive(ctx context.Context) (bool, error) {
 	metricValues, err := s.getSolaceQueueMetricsFromSEMP(ctx)
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"k8s.io/api/autoscaling/v2beta2"
@@ -17,15 +18,16 @@ type testSolaceMetadata struct {
 }
 
 var (
-	soltestValidBaseURL        = "http://localhost:8080"
-	soltestValidUsername       = "admin"
-	soltestValidPassword       = "admin"
-	soltestValidVpn            = "dennis_vpn"
-	soltestValidQueueName      = "queue3"
-	soltestValidMsgCountTarget = "10"
-	soltestValidMsgSpoolTarget = "20"
-	soltestEnvUsername         = "SOLTEST_USERNAME"
-	soltestEnvPassword         = "SOLTEST_PASSWORD"
+	soltestValidBaseURL          = "http://localhost:8080"
+	soltestValidUsername         = "admin"
+	soltestValidPassword         = "admin"
+	soltestValidVpn              = "dennis_vpn"
+	soltestValidQueueName        = "queue3"
+	soltestValidMsgCountTarget   = "10"
+	soltestValidMsgSpoolTarget   = "20"
+	soltestValidSpoolUsageTarget = "80"
+	soltestEnvUsername           = "SOLTEST_USERNAME"
+	soltestEnvPassword           = "SOLTEST_PASSWORD"
 )
 
 // AUTH RECORD FOR TEST
@@ -365,11 +367,51 @@ var testSolaceGetMetricSpecData = []testSolaceMetadata{
 		1,
 		false,
 	},
+	{
+		"#407 - Get Metric Spec - spoolUsageTarget",
+		map[string]string{
+			solaceMetaSempBaseURL:      soltestValidBaseURL,
+			solaceMetaMsgVpn:           soltestValidVpn,
+			solaceMetaUsername:         soltestValidUsername,
+			solaceMetaPassword:         soltestValidPassword,
+			solaceMetaQueueName:        soltestValidQueueName,
+			solaceMetaSpoolUsageTarget: soltestValidSpoolUsageTarget,
+		},
+		1,
+		false,
+	},
+	{
+		"#408 - Get Metric Spec - spoolUsageTarget over 100 percent",
+		map[string]string{
+			solaceMetaSempBaseURL:      soltestValidBaseURL,
+			solaceMetaMsgVpn:           soltestValidVpn,
+			solaceMetaUsername:         soltestValidUsername,
+			solaceMetaPassword:         soltestValidPassword,
+			solaceMetaQueueName:        soltestValidQueueName,
+			solaceMetaSpoolUsageTarget: "120",
+		},
+		1,
+		true,
+	},
+	{
+		"#409 - Get Metric Spec - INVALID spoolUsageTarget",
+		map[string]string{
+			solaceMetaSempBaseURL:      soltestValidBaseURL,
+			solaceMetaMsgVpn:           soltestValidVpn,
+			solaceMetaUsername:         soltestValidUsername,
+			solaceMetaPassword:         soltestValidPassword,
+			solaceMetaQueueName:        soltestValidQueueName,
+			solaceMetaSpoolUsageTarget: "half",
+		},
+		1,
+		true,
+	},
 }
 
 var testSolaceExpectedMetricNames = map[string]string{
 	"s1-" + solaceScalerID + "-" + soltestValidQueueName + "-" + solaceTriggermsgcount:      "",
 	"s1-" + solaceScalerID + "-" + soltestValidQueueName + "-" + solaceTriggermsgspoolusage: "",
+	"s1-" + solaceScalerID + "-" + soltestValidVpn + "-" + solaceTriggervpnspoolusage:       "",
 }
 
 func TestSolaceParseSolaceMetadata(t *testing.T) {
@@ -455,3 +497,46 @@ func TestSolaceGetMetricSpec(t *testing.T) {
 		}
 	}
 }
+
+func TestSolaceGetMetricsVpnSpoolUsage(t *testing.T) {
+	var paths []string
+	sempStub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		// 300MB used of a 1000MB quota
+		_, _ = w.Write([]byte(`{"data":{"msgSpoolUsage":314572800,"maxMsgSpoolUsage":1000},"meta":{"responseCode":200}}`))
+	}))
+	defer sempStub.Close()
+
+	solaceMeta, err := parseSolaceMetadata(&ScalerConfig{
+		TriggerMetadata: map[string]string{
+			solaceMetaSempBaseURL:      sempStub.URL,
+			solaceMetaMsgVpn:           soltestValidVpn,
+			solaceMetaQueueName:        soltestValidQueueName,
+			solaceMetaSpoolUsageTarget: soltestValidSpoolUsageTarget,
+		},
+		AuthParams: testDataSolaceAuthParamsVALID,
+	})
+	if err != nil {
+		t.Fatal("Could not parse metadata:", err)
+	}
+	testSolaceScaler := SolaceScaler{
+		metadata:   solaceMeta,
+		httpClient: http.DefaultClient,
+	}
+
+	metricSpec := testSolaceScaler.GetMetricSpecForScaling(context.Background())[0]
+	if target := metricSpec.External.Target; target.Type != v2beta2.ValueMetricType || target.Value.Value() != 80 || target.AverageValue != nil {
+		t.Errorf("Expected a Value target of 80, got %+v", target)
+	}
+	metricName := metricSpec.External.Metric.Name
+	metrics, err := testSolaceScaler.GetMetrics(context.Background(), metricName, nil)
+	if err != nil {
+		t.Fatal("Expected success but got error", err)
+	}
+	if value := metrics[0].Value.MilliValue(); value != 30000 {
+		t.Errorf("Wrong spool usage %dm, expected 30000m", value)
+	}
+	if len(paths) != 1 || paths[0] != "/SEMP/v2/monitor/msgVpns/"+soltestValidVpn {
+		t.Errorf("Expected the Message VPN to be read, got %v", paths)
+	}
+}
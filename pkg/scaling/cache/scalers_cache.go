@@ -26,6 +26,7 @@ import (
 	"github.com/kedacore/keda/v2/pkg/scalers"
 	"k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/metrics/pkg/apis/external_metrics"
@@ -79,17 +80,44 @@ func (c *ScalersCache) GetMetricsForScaler(ctx context.Context, id int, metricNa
 	return ns.GetMetrics(ctx, metricName, metricSelector)
 }
 
+// HealthCheck checks the backends of the scalers, the result holds the error of each scaler in trigger order, nil when healthy
+func (c *ScalersCache) HealthCheck(ctx context.Context) []error {
+	result := make([]error, 0, len(c.Scalers))
+	for _, s := range c.Scalers {
+		result = append(result, scalers.HealthCheck(ctx, s.Scaler))
+	}
+	return result
+}
+
+// LastMetricValues returns the last value read by the scalers able to report it, by external metric name
+func (c *ScalersCache) LastMetricValues(ctx context.Context) map[string]resource.Quantity {
+	result := make(map[string]resource.Quantity)
+	for _, s := range c.Scalers {
+		value, ok := scalers.LastMetricValue(s.Scaler)
+		if !ok {
+			continue
+		}
+		for _, metricSpec := range s.Scaler.GetMetricSpecForScaling(ctx) {
+			if metricSpec.External != nil {
+				result[metricSpec.External.Metric.Name] = *resource.NewMilliQuantity(int64(value*1000), resource.DecimalSI)
+			}
+		}
+	}
+	return result
+}
+
 func (c *ScalersCache) IsScaledObjectActive(ctx context.Context, scaledObject *kedav1alpha1.ScaledObject) (bool, bool, []external_metrics.ExternalMetricValue) {
 	isActive := false
 	isError := false
+	metrics := []external_metrics.ExternalMetricValue{}
 	// Let's collect status of all scalers, no matter if any scaler raises error or is active
 	for i, s := range c.Scalers {
-		isTriggerActive, err := s.Scaler.IsActive(ctx)
+		isTriggerActive, triggerMetrics, err := isScalerActive(ctx, s.Scaler)
 		if err != nil {
 			var ns scalers.Scaler
 			ns, err = c.refreshScaler(ctx, i)
 			if err == nil {
-				isTriggerActive, err = ns.IsActive(ctx)
+				isTriggerActive, triggerMetrics, err = isScalerActive(ctx, ns)
 			}
 		}
 
@@ -100,7 +128,10 @@ func (c *ScalersCache) IsScaledObjectActive(ctx context.Context, scaledObject *k
 			isError = true
 			logger.Error(err, "Error getting scale decision")
 			c.Recorder.Event(scaledObject, corev1.EventTypeWarning, eventreason.KEDAScalerFailed, err.Error())
-		} else if isTriggerActive {
+			continue
+		}
+		metrics = append(metrics, triggerMetrics...)
+		if isTriggerActive {
 			isActive = true
 			if externalMetricsSpec := s.Scaler.GetMetricSpecForScaling(ctx)[0].External; externalMetricsSpec != nil {
 				logger.V(1).Info("Scaler for scaledObject is active", "Metrics Name", externalMetricsSpec.Metric.Name)
@@ -111,7 +142,20 @@ func (c *ScalersCache) IsScaledObjectActive(ctx context.Context, scaledObject *k
 		}
 	}
 
-	return isActive, isError, []external_metrics.ExternalMetricValue{}
+	return isActive, isError, metrics
+}
+
+// isScalerActive returns whether the scaler is active. The scalers implementing MetricsAndActivityScaler also return
+// their metrics, read by the same poll of their backend, the others are only asked for their activity.
+func isScalerActive(ctx context.Context, scaler scalers.Scaler) (bool, []external_metrics.ExternalMetricValue, error) {
+	if _, ok := scaler.(scalers.MetricsAndActivityScaler); ok {
+		if metricSpecs := scaler.GetMetricSpecForScaling(ctx); len(metricSpecs) > 0 && metricSpecs[0].External != nil {
+			metrics, isActive, err := scalers.GetMetricsAndActivity(ctx, scaler, metricSpecs[0].External.Metric.Name, nil)
+			return isActive, metrics, err
+		}
+	}
+	isActive, err := scaler.IsActive(ctx)
+	return isActive, nil, err
 }
 
 func (c *ScalersCache) IsScaledJobActive(ctx context.Context, scaledJob *kedav1alpha1.ScaledJob) (bool, int64, int64) {
@@ -253,30 +297,23 @@ func (c *ScalersCache) getScaledJobMetrics(ctx context.Context, scaledJob *kedav
 			continue
 		}
 
-		isTriggerActive, err := s.Scaler.IsActive(ctx)
+		metrics, isTriggerActive, err := scalers.GetMetricsAndActivity(ctx, s.Scaler, "queueLength", nil)
 		if err != nil {
 			var ns scalers.Scaler
 			ns, err = c.refreshScaler(ctx, i)
 			if err == nil {
-				isTriggerActive, err = ns.IsActive(ctx)
+				metrics, isTriggerActive, err = scalers.GetMetricsAndActivity(ctx, ns, "queueLength", nil)
 			}
 		}
 
 		if err != nil {
-			scalerLogger.V(1).Info("Error getting scaler.IsActive, but continue", "Error", err)
+			scalerLogger.V(1).Info("Error getting scaler metrics and activity, but continue", "Error", err)
 			c.Recorder.Event(scaledJob, corev1.EventTypeWarning, eventreason.KEDAScalerFailed, err.Error())
 			continue
 		}
 
 		targetAverageValue = getTargetAverageValue(metricSpecs)
 
-		metrics, err := s.Scaler.GetMetrics(ctx, "queueLength", nil)
-		if err != nil {
-			scalerLogger.V(1).Info("Error getting scaler metrics, but continue", "Error", err)
-			c.Recorder.Event(scaledJob, corev1.EventTypeWarning, eventreason.KEDAScalerFailed, err.Error())
-			continue
-		}
-
 		var metricValue int64
 
 		for _, m := range metrics {
@@ -247,3 +247,90 @@ func createScaler(ctrl *gomock.Controller, queueLength int64, averageValue int32
 	scaler.EXPECT().Close(gomock.Any())
 	return scaler
 }
+
+// lastMetricValueScaler adds LastMetricValue to a mock scaler
+type lastMetricValueScaler struct {
+	*mock_scalers.MockScaler
+	value float64
+	ok    bool
+}
+
+func (s *lastMetricValueScaler) LastMetricValue() (float64, bool) {
+	return s.value, s.ok
+}
+
+func TestLastMetricValues(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	newScaler := func(metricName string, value float64, ok bool) scalers.Scaler {
+		scaler := mock_scalers.NewMockScaler(ctrl)
+		scaler.EXPECT().GetMetricSpecForScaling(gomock.Any()).Return([]v2beta2.MetricSpec{{
+			External: &v2beta2.ExternalMetricSource{Metric: v2beta2.MetricIdentifier{Name: metricName}},
+		}}).AnyTimes()
+		return &lastMetricValueScaler{MockScaler: scaler, value: value, ok: ok}
+	}
+
+	cache := ScalersCache{
+		Scalers: []ScalerBuilder{
+			{Scaler: newScaler("s0-activemq-orders", 42, true)},
+			{Scaler: newScaler("s1-activemq-invoices", 0.5, true)},
+			// nothing read yet
+			{Scaler: newScaler("s2-activemq-payments", 0, false)},
+			// not implementing LastMetricValueScaler
+			{Scaler: mock_scalers.NewMockScaler(ctrl)},
+		},
+		Logger: logr.Discard(),
+	}
+
+	values := cache.LastMetricValues(context.Background())
+	assert.Equal(t, 2, len(values))
+	orders := values["s0-activemq-orders"]
+	assert.Equal(t, "42", orders.String())
+	invoices := values["s1-activemq-invoices"]
+	assert.Equal(t, "500m", invoices.String())
+}
+
+// metricsAndActivityScaler adds GetMetricsAndActivity to a mock scaler, which fails if IsActive or GetMetrics is called
+type metricsAndActivityScaler struct {
+	*mock_scalers.MockScaler
+	value    int64
+	isActive bool
+	calls    int
+}
+
+func (s *metricsAndActivityScaler) GetMetricsAndActivity(_ context.Context, metricName string) ([]external_metrics.ExternalMetricValue, bool, error) {
+	s.calls++
+	return []external_metrics.ExternalMetricValue{{MetricName: metricName, Value: *resource.NewQuantity(s.value, resource.DecimalSI)}}, s.isActive, nil
+}
+
+func TestMetricsAndActivityScaler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockScaler := mock_scalers.NewMockScaler(ctrl)
+	metricSpec := createMetricSpec(2)
+	metricSpec.External.Metric.Name = "s0-activemq-orders"
+	mockScaler.EXPECT().GetMetricSpecForScaling(gomock.Any()).Return([]v2beta2.MetricSpec{metricSpec}).AnyTimes()
+	scaler := &metricsAndActivityScaler{MockScaler: mockScaler, value: 20, isActive: true}
+	cache := ScalersCache{
+		Scalers:  []ScalerBuilder{{Scaler: scaler}},
+		Logger:   logr.Discard(),
+		Recorder: record.NewFakeRecorder(1),
+	}
+
+	// the ScaledObject activity comes with the metrics from a single poll
+	isActive, isError, metrics := cache.IsScaledObjectActive(context.Background(), &kedav1alpha1.ScaledObject{
+		Spec: kedav1alpha1.ScaledObjectSpec{ScaleTargetRef: &kedav1alpha1.ScaleTarget{Name: "test"}},
+	})
+	assert.True(t, isActive)
+	assert.False(t, isError)
+	assert.Equal(t, 1, len(metrics))
+	assert.Equal(t, "s0-activemq-orders", metrics[0].MetricName)
+	assert.Equal(t, 1, scaler.calls)
+
+	// as does the ScaledJob queue length
+	isActive, queueLength, maxValue := cache.IsScaledJobActive(context.Background(), createScaledObject(100, ""))
+	assert.True(t, isActive)
+	assert.Equal(t, int64(20), queueLength)
+	assert.Equal(t, int64(10), maxValue)
+	assert.Equal(t, 2, scaler.calls)
+}
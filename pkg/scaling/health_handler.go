@@ -0,0 +1,228 @@
+/*
+Copyright 2021 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaling
+
+import (
+	"context"
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	"github.com/kedacore/keda/v2/pkg/scalers"
+)
+
+// ScalersHealthPath is the path the health of the scalers of a ScaledObject is served on
+const ScalersHealthPath = "/scalers/health"
+
+// scalersHealthTTL is how long the health of the scalers of a ScaledObject is served from the cache, the backends
+// are checked at most once per ScaledObject in that time however often the endpoint is called
+const scalersHealthTTL = 30 * time.Second
+
+// scalersHealthTimeout bounds a check of the backends of a ScaledObject, and how long a request waits for it. A check
+// still running after that is replaced by a new one, so a backend that never answers can't wedge the health report.
+const scalersHealthTimeout = 10 * time.Second
+
+// Error kinds reported for an unhealthy trigger, the errors themselves are only logged as they may name internal
+// hosts or credentials
+const (
+	TriggerErrorConfig      = "config"
+	TriggerErrorAuth        = "auth"
+	TriggerErrorUnreachable = "unreachable"
+	TriggerErrorUnknown     = "unknown"
+)
+
+// TriggerHealth is the health of the scaler of one trigger
+type TriggerHealth struct {
+	Index   int    `json:"index"`
+	Type    string `json:"type"`
+	Healthy bool   `json:"healthy"`
+	Error   string `json:"error,omitempty"`
+}
+
+// ScalersHealth is the health of the scalers of a ScaledObject
+type ScalersHealth struct {
+	Namespace string          `json:"namespace"`
+	Name      string          `json:"name"`
+	Healthy   bool            `json:"healthy"`
+	Triggers  []TriggerHealth `json:"triggers"`
+}
+
+// scalersHealthEntry is the cached health of a generation of a ScaledObject, done is closed once it is checked
+type scalersHealthEntry struct {
+	generation int64
+	startedAt  time.Time
+	checkedAt  time.Time
+	done       chan struct{}
+	health     ScalersHealth
+	err        error
+}
+
+type healthHandler struct {
+	client       client.Client
+	scaleHandler ScaleHandler
+	now          func() time.Time
+	lock         sync.Mutex
+	entries      map[types.NamespacedName]*scalersHealthEntry
+}
+
+// NewHealthHandler returns the handler reporting whether the backends of the scalers of the ScaledObject given by
+// the namespace and name query parameters are reachable. It responds with 503 if any of them isn't. The handler
+// calls the backends, it is meant to be served on its own listener rather than along with the metrics.
+func NewHealthHandler(client client.Client, scaleHandler ScaleHandler) http.Handler {
+	return &healthHandler{
+		client:       client,
+		scaleHandler: scaleHandler,
+		now:          time.Now,
+		entries:      map[types.NamespacedName]*scalersHealthEntry{},
+	}
+}
+
+func (h *healthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logf.Log.WithName("scalers_health")
+	namespace, name := r.URL.Query().Get("namespace"), r.URL.Query().Get("name")
+	if namespace == "" || name == "" {
+		http.Error(w, "the namespace and name query parameters are required", http.StatusBadRequest)
+		return
+	}
+
+	scaledObject := &kedav1alpha1.ScaledObject{}
+	if err := h.client.Get(r.Context(), types.NamespacedName{Namespace: namespace, Name: name}, scaledObject); err != nil {
+		if errors.IsNotFound(err) {
+			h.lock.Lock()
+			delete(h.entries, types.NamespacedName{Namespace: namespace, Name: name})
+			h.lock.Unlock()
+			http.Error(w, fmt.Sprintf("ScaledObject %s/%s not found", namespace, name), http.StatusNotFound)
+			return
+		}
+		logger.Error(err, "error getting ScaledObject", "namespace", namespace, "name", name)
+		http.Error(w, "error getting the ScaledObject", http.StatusInternalServerError)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), scalersHealthTimeout)
+	defer cancel()
+	health, err := h.getHealth(ctx, scaledObject)
+	if err != nil {
+		if goerrors.Is(err, context.DeadlineExceeded) {
+			http.Error(w, "timed out checking the scalers", http.StatusGatewayTimeout)
+			return
+		}
+		http.Error(w, "error getting the scalers", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !health.Healthy {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	if err := json.NewEncoder(w).Encode(health); err != nil {
+		logger.Error(err, "error writing the scalers health")
+	}
+}
+
+// getHealth returns the cached health of the ScaledObject, or checks it when the cached one is older than
+// scalersHealthTTL, of another generation or still being checked after scalersHealthTimeout. Concurrent requests
+// for the same ScaledObject share a single check, and each waits for it until its ctx is done.
+func (h *healthHandler) getHealth(ctx context.Context, scaledObject *kedav1alpha1.ScaledObject) (ScalersHealth, error) {
+	key := types.NamespacedName{Namespace: scaledObject.Namespace, Name: scaledObject.Name}
+
+	h.lock.Lock()
+	entry, ok := h.entries[key]
+	if !ok || entry.generation != scaledObject.Generation || h.isExpired(entry) {
+		entry = &scalersHealthEntry{generation: scaledObject.Generation, startedAt: h.now(), done: make(chan struct{})}
+		h.entries[key] = entry
+		// the check isn't tied to the request, the requests waiting for it may outlive this one
+		go h.check(entry, scaledObject.DeepCopy())
+	}
+	h.lock.Unlock()
+
+	select {
+	case <-entry.done:
+		return entry.health, entry.err
+	case <-ctx.Done():
+		return ScalersHealth{}, ctx.Err()
+	}
+}
+
+// check checks the health of the ScaledObject within scalersHealthTimeout and completes the entry
+func (h *healthHandler) check(entry *scalersHealthEntry, scaledObject *kedav1alpha1.ScaledObject) {
+	ctx, cancel := context.WithTimeout(context.Background(), scalersHealthTimeout)
+	defer cancel()
+	health, err := h.checkHealth(ctx, scaledObject)
+
+	h.lock.Lock()
+	entry.health, entry.err = health, err
+	entry.checkedAt = h.now()
+	h.lock.Unlock()
+	close(entry.done)
+}
+
+// isExpired reports whether the entry was checked more than scalersHealthTTL ago, or is still being checked more
+// than scalersHealthTimeout after the check started
+func (h *healthHandler) isExpired(entry *scalersHealthEntry) bool {
+	if entry.checkedAt.IsZero() {
+		return h.now().Sub(entry.startedAt) > scalersHealthTimeout
+	}
+	return h.now().Sub(entry.checkedAt) > scalersHealthTTL
+}
+
+func (h *healthHandler) checkHealth(ctx context.Context, scaledObject *kedav1alpha1.ScaledObject) (ScalersHealth, error) {
+	logger := logf.Log.WithName("scalers_health")
+	cache, err := h.scaleHandler.GetScalersCache(ctx, scaledObject)
+	if err != nil {
+		logger.Error(err, "error getting scalers", "namespace", scaledObject.Namespace, "name", scaledObject.Name)
+		return ScalersHealth{}, err
+	}
+
+	health := ScalersHealth{Namespace: scaledObject.Namespace, Name: scaledObject.Name, Healthy: true, Triggers: []TriggerHealth{}}
+	for i, err := range cache.HealthCheck(ctx) {
+		trigger := TriggerHealth{Index: i, Healthy: err == nil}
+		if i < len(scaledObject.Spec.Triggers) {
+			trigger.Type = scaledObject.Spec.Triggers[i].Type
+		}
+		if err != nil {
+			logger.Error(err, "unhealthy scaler", "namespace", scaledObject.Namespace, "name", scaledObject.Name, "triggerIndex", i)
+			trigger.Error = triggerErrorKind(err)
+			health.Healthy = false
+		}
+		health.Triggers = append(health.Triggers, trigger)
+	}
+	return health, nil
+}
+
+// triggerErrorKind returns the kind of a health check error
+func triggerErrorKind(err error) string {
+	switch {
+	case goerrors.Is(err, scalers.ErrConfig):
+		return TriggerErrorConfig
+	case goerrors.Is(err, scalers.ErrAuth):
+		return TriggerErrorAuth
+	case goerrors.Is(err, scalers.ErrUnreachable):
+		return TriggerErrorUnreachable
+	default:
+		return TriggerErrorUnknown
+	}
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2021 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaling
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	mock_scalers "github.com/kedacore/keda/v2/pkg/mock/mock_scaler"
+	"github.com/kedacore/keda/v2/pkg/mock/mock_scaling"
+	"github.com/kedacore/keda/v2/pkg/scalers"
+	"github.com/kedacore/keda/v2/pkg/scaling/cache"
+)
+
+type healthCheckScaler struct {
+	scalers.Scaler
+	err   error
+	block <-chan struct{}
+}
+
+func (s *healthCheckScaler) HealthCheck(context.Context) error {
+	if s.block != nil {
+		// a backend that never answers, whatever the context
+		<-s.block
+	}
+	return s.err
+}
+
+func TestHealthHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	scheme := runtime.NewScheme()
+	assert.Nil(t, kedav1alpha1.AddToScheme(scheme))
+
+	scaledObject := &kedav1alpha1.ScaledObject{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+		Spec: kedav1alpha1.ScaledObjectSpec{
+			Triggers: []kedav1alpha1.ScaleTriggers{{Type: "activemq"}, {Type: "cron"}},
+		},
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(scaledObject).Build()
+
+	testCases := []struct {
+		name     string
+		query    string
+		err      error
+		status   int
+		triggers []TriggerHealth
+	}{
+		{"healthy", "?namespace=test&name=test", nil, http.StatusOK, []TriggerHealth{
+			{Index: 0, Type: "activemq", Healthy: true},
+			{Index: 1, Type: "cron", Healthy: true},
+		}},
+		{"unreachable", "?namespace=test&name=test", fmt.Errorf("dial tcp broker.internal:8161: %w", scalers.ErrUnreachable), http.StatusServiceUnavailable, []TriggerHealth{
+			{Index: 0, Type: "activemq", Healthy: false, Error: TriggerErrorUnreachable},
+			{Index: 1, Type: "cron", Healthy: true},
+		}},
+		{"rejected credentials", "?namespace=test&name=test", fmt.Errorf("status 401 for user admin: %w", scalers.ErrAuth), http.StatusServiceUnavailable, []TriggerHealth{
+			{Index: 0, Type: "activemq", Healthy: false, Error: TriggerErrorAuth},
+			{Index: 1, Type: "cron", Healthy: true},
+		}},
+		{"unclassified error", "?namespace=test&name=test", errors.New("connection refused"), http.StatusServiceUnavailable, []TriggerHealth{
+			{Index: 0, Type: "activemq", Healthy: false, Error: TriggerErrorUnknown},
+			{Index: 1, Type: "cron", Healthy: true},
+		}},
+		{"missing name", "?namespace=test", nil, http.StatusBadRequest, nil},
+		{"unknown ScaledObject", "?namespace=test&name=other", nil, http.StatusNotFound, nil},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			scaleHandler := mock_scaling.NewMockScaleHandler(ctrl)
+			if testCase.triggers != nil {
+				scaleHandler.EXPECT().GetScalersCache(gomock.Any(), gomock.Any()).Return(newHealthCheckCache(ctrl, testCase.err), nil)
+			}
+
+			recorder := httptest.NewRecorder()
+			NewHealthHandler(client, scaleHandler).ServeHTTP(recorder, httptest.NewRequest("GET", ScalersHealthPath+testCase.query, nil))
+			assert.Equal(t, testCase.status, recorder.Code)
+			if testCase.triggers == nil {
+				return
+			}
+
+			var health ScalersHealth
+			assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &health))
+			assert.Equal(t, testCase.err == nil, health.Healthy)
+			assert.Equal(t, testCase.triggers, health.Triggers)
+			assert.NotContains(t, recorder.Body.String(), "broker.internal")
+			assert.NotContains(t, recorder.Body.String(), "admin")
+		})
+	}
+}
+
+func TestHealthHandlerCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	scheme := runtime.NewScheme()
+	assert.Nil(t, kedav1alpha1.AddToScheme(scheme))
+
+	scaledObject := &kedav1alpha1.ScaledObject{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test", Generation: 1},
+		Spec: kedav1alpha1.ScaledObjectSpec{
+			Triggers: []kedav1alpha1.ScaleTriggers{{Type: "activemq"}, {Type: "cron"}},
+		},
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(scaledObject).Build()
+	scaleHandler := mock_scaling.NewMockScaleHandler(ctrl)
+	handler := NewHealthHandler(client, scaleHandler).(*healthHandler)
+	now := time.Now()
+	handler.now = func() time.Time { return now }
+
+	get := func() int {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", ScalersHealthPath+"?namespace=test&name=test", nil))
+		return recorder.Code
+	}
+
+	// the backends are checked once, the following requests are served from the cache
+	scaleHandler.EXPECT().GetScalersCache(gomock.Any(), gomock.Any()).Return(newHealthCheckCache(ctrl, nil), nil)
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusOK, get())
+	}
+
+	// checked again once the cached health expired
+	now = now.Add(scalersHealthTTL + time.Second)
+	scaleHandler.EXPECT().GetScalersCache(gomock.Any(), gomock.Any()).Return(newHealthCheckCache(ctrl, scalers.ErrUnreachable), nil)
+	assert.Equal(t, http.StatusServiceUnavailable, get())
+	assert.Equal(t, http.StatusServiceUnavailable, get())
+
+	// and when the ScaledObject changed
+	scaledObject.Generation = 2
+	assert.Nil(t, client.Update(context.Background(), scaledObject))
+	scaleHandler.EXPECT().GetScalersCache(gomock.Any(), gomock.Any()).Return(newHealthCheckCache(ctrl, nil), nil)
+	assert.Equal(t, http.StatusOK, get())
+
+	// the error getting the scalers isn't written back
+	now = now.Add(scalersHealthTTL + time.Second)
+	scaleHandler.EXPECT().GetScalersCache(gomock.Any(), gomock.Any()).Return(nil, errors.New("secret broker-credentials not found"))
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", ScalersHealthPath+"?namespace=test&name=test", nil))
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.NotContains(t, recorder.Body.String(), "broker-credentials")
+}
+
+func TestHealthHandlerHungCheck(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	scheme := runtime.NewScheme()
+	assert.Nil(t, kedav1alpha1.AddToScheme(scheme))
+
+	scaledObject := &kedav1alpha1.ScaledObject{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test", Generation: 1},
+		Spec: kedav1alpha1.ScaledObjectSpec{
+			Triggers: []kedav1alpha1.ScaleTriggers{{Type: "activemq"}},
+		},
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(scaledObject).Build()
+	scaleHandler := mock_scaling.NewMockScaleHandler(ctrl)
+	handler := NewHealthHandler(client, scaleHandler).(*healthHandler)
+	now := time.Now()
+	handler.now = func() time.Time { return now }
+
+	get := func() int {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", ScalersHealthPath+"?namespace=test&name=test", nil).WithContext(ctx))
+		return recorder.Code
+	}
+
+	// the requests give up waiting for a check that doesn't return, without starting another one
+	block := make(chan struct{})
+	defer close(block)
+	hung := &cache.ScalersCache{Scalers: []cache.ScalerBuilder{{Scaler: &healthCheckScaler{Scaler: mock_scalers.NewMockScaler(ctrl), block: block}}}}
+	scaleHandler.EXPECT().GetScalersCache(gomock.Any(), gomock.Any()).Return(hung, nil)
+	assert.Equal(t, http.StatusGatewayTimeout, get())
+	assert.Equal(t, http.StatusGatewayTimeout, get())
+
+	// the check is replaced once it has been running for longer than scalersHealthTimeout
+	now = now.Add(scalersHealthTimeout + time.Second)
+	scaleHandler.EXPECT().GetScalersCache(gomock.Any(), gomock.Any()).Return(newHealthCheckCache(ctrl, nil), nil)
+	assert.Equal(t, http.StatusOK, get())
+}
+
+// newHealthCheckCache returns a cache whose first scaler checks its backend, failing with err, and whose second
+// scaler doesn't implement HealthCheckScaler
+func newHealthCheckCache(ctrl *gomock.Controller, err error) *cache.ScalersCache {
+	return &cache.ScalersCache{
+		Scalers: []cache.ScalerBuilder{
+			{Scaler: &healthCheckScaler{Scaler: mock_scalers.NewMockScaler(ctrl), err: err}},
+			{Scaler: mock_scalers.NewMockScaler(ctrl)},
+		},
+	}
+}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/scale"
@@ -39,6 +40,10 @@ import (
 	"github.com/kedacore/keda/v2/pkg/scaling/resolver"
 )
 
+// lastMetricValuesMinInterval is the minimum time between two updates of the last metric values in the status of a
+// ScaledObject, unless its activity changed, to keep busy triggers from patching the status on every poll
+const lastMetricValuesMinInterval = time.Minute
+
 // ScaleHandler encapsulates the logic of calling the right scalers for
 // each ScaledObject and making the final scale decision and operation
 type ScaleHandler interface {
@@ -57,6 +62,14 @@ type scaleHandler struct {
 	recorder          record.EventRecorder
 	scalerCaches      map[string]*cache.ScalersCache
 	lock              *sync.RWMutex
+	// last update of the last metric values of each ScaledObject, by namespaced name
+	lastMetricValuesUpdates *sync.Map
+}
+
+// lastMetricValuesUpdate is the time and activity of the last update of the last metric values of a ScaledObject
+type lastMetricValuesUpdate struct {
+	at     time.Time
+	active bool
 }
 
 // NewScaleHandler creates a ScaleHandler object
@@ -70,6 +83,8 @@ func NewScaleHandler(client client.Client, scaleClient scale.ScalesGetter, recon
 		recorder:          recorder,
 		scalerCaches:      map[string]*cache.ScalersCache{},
 		lock:              &sync.RWMutex{},
+
+		lastMetricValuesUpdates: &sync.Map{},
 	}
 }
 
@@ -117,6 +132,7 @@ func (h *scaleHandler) DeleteScalableObject(ctx context.Context, scalableObject
 		return err
 	}
 
+	h.lastMetricValuesUpdates.Delete(types.NamespacedName{Namespace: withTriggers.Namespace, Name: withTriggers.Name})
 	key := withTriggers.GenerateIdenitifier()
 	result, ok := h.scaleLoopContexts.Load(key)
 	if ok {
@@ -276,6 +292,7 @@ func (h *scaleHandler) checkScalers(ctx context.Context, scalableObject interfac
 			return
 		}
 		isActive, isError, _ := cache.IsScaledObjectActive(ctx, obj)
+		h.updateLastMetricValues(ctx, obj, cache.LastMetricValues(ctx), isActive)
 		h.scaleExecutor.RequestScale(ctx, obj, isActive, isError)
 	case *kedav1alpha1.ScaledJob:
 		err = h.client.Get(ctx, types.NamespacedName{Name: obj.Name, Namespace: obj.Namespace}, obj)
@@ -288,6 +305,40 @@ func (h *scaleHandler) checkScalers(ctx context.Context, scalableObject interfac
 	}
 }
 
+// updateLastMetricValues reports the last values read by the scalers in the ScaledObject status. The status is
+// only patched when a value has changed, at most once per lastMetricValuesMinInterval unless the ScaledObject
+// became active or inactive.
+func (h *scaleHandler) updateLastMetricValues(ctx context.Context, scaledObject *kedav1alpha1.ScaledObject, values map[string]resource.Quantity, isActive bool) {
+	if len(values) == 0 && len(scaledObject.Status.LastMetricValues) == 0 {
+		return
+	}
+	changed := len(values) != len(scaledObject.Status.LastMetricValues)
+	for name, value := range values {
+		if current, ok := scaledObject.Status.LastMetricValues[name]; !ok || current.Cmp(value) != 0 {
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+
+	key := types.NamespacedName{Namespace: scaledObject.Namespace, Name: scaledObject.Name}
+	if last, ok := h.lastMetricValuesUpdates.Load(key); ok {
+		last := last.(lastMetricValuesUpdate)
+		if last.active == isActive && time.Since(last.at) < lastMetricValuesMinInterval {
+			return
+		}
+	}
+
+	patch := client.MergeFrom(scaledObject.DeepCopy())
+	scaledObject.Status.LastMetricValues = values
+	if err := h.client.Status().Patch(ctx, scaledObject, patch); err != nil {
+		h.logger.Error(err, "Error updating the last metric values", "scaledObject.Namespace", scaledObject.Namespace, "scaledObject.Name", scaledObject.Name)
+		return
+	}
+	h.lastMetricValuesUpdates.Store(key, lastMetricValuesUpdate{at: time.Now(), active: isActive})
+}
+
 // buildScalers returns list of Scalers for the specified triggers
 func (h *scaleHandler) buildScalers(ctx context.Context, withTriggers *kedav1alpha1.WithTriggers, podTemplateSpec *corev1.PodTemplateSpec, containerName string) ([]cache.ScalerBuilder, error) {
 	logger := h.logger.WithValues("type", withTriggers.Kind, "namespace", withTriggers.Namespace, "name", withTriggers.Name)
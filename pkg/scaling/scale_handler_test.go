@@ -20,13 +20,17 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"k8s.io/api/autoscaling/v2beta2"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
@@ -134,6 +138,51 @@ func TestCheckScaledObjectFindFirstActiveNotIgnoreOthers(t *testing.T) {
 	assert.Equal(t, true, isError)
 }
 
+func TestUpdateLastMetricValues(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.Nil(t, kedav1alpha1.AddToScheme(scheme))
+	key := types.NamespacedName{Namespace: "test", Name: "test"}
+	client := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(&kedav1alpha1.ScaledObject{
+		ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+	}).Build()
+	h := NewScaleHandler(client, nil, scheme, time.Second, record.NewFakeRecorder(1)).(*scaleHandler)
+
+	update := func(value int64, isActive bool) {
+		scaledObject := &kedav1alpha1.ScaledObject{}
+		assert.Nil(t, client.Get(context.Background(), key, scaledObject))
+		h.updateLastMetricValues(context.Background(), scaledObject, map[string]resource.Quantity{"s0-activemq-testQueue": *resource.NewQuantity(value, resource.DecimalSI)}, isActive)
+	}
+	lastMetricValue := func() int64 {
+		scaledObject := &kedav1alpha1.ScaledObject{}
+		assert.Nil(t, client.Get(context.Background(), key, scaledObject))
+		value := scaledObject.Status.LastMetricValues["s0-activemq-testQueue"]
+		return value.Value()
+	}
+
+	update(3, true)
+	assert.Equal(t, int64(3), lastMetricValue())
+
+	// a changed value is only reported after the minimum interval
+	update(4, true)
+	assert.Equal(t, int64(3), lastMetricValue())
+
+	// unless the activity changed
+	update(0, false)
+	assert.Equal(t, int64(0), lastMetricValue())
+
+	last, _ := h.lastMetricValuesUpdates.Load(key)
+	h.lastMetricValuesUpdates.Store(key, lastMetricValuesUpdate{at: last.(lastMetricValuesUpdate).at.Add(-lastMetricValuesMinInterval), active: false})
+	update(5, false)
+	assert.Equal(t, int64(5), lastMetricValue())
+	update(6, false)
+	assert.Equal(t, int64(5), lastMetricValue())
+
+	// the throttling starts over for a recreated ScaledObject
+	assert.Nil(t, h.DeleteScalableObject(context.Background(), &kedav1alpha1.ScaledObject{ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}}))
+	_, ok := h.lastMetricValuesUpdates.Load(key)
+	assert.False(t, ok)
+}
+
 func createMetricSpec(averageValue int) v2beta2.MetricSpec {
 	qty := resource.NewQuantity(int64(averageValue), resource.DecimalSI)
 	return v2beta2.MetricSpec{
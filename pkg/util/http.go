@@ -18,7 +18,9 @@ package util
 
 import (
 	"crypto/tls"
+	"net"
 	"net/http"
+	"net/url"
 	"time"
 )
 
@@ -29,19 +31,72 @@ type HTTPDoer interface {
 	Do(*http.Request) (*http.Response, error)
 }
 
+// HTTPClientOption customizes the transport of the clients created by CreateHTTPClient
+type HTTPClientOption func(*http.Transport)
+
+// WithProxy routes the requests through the proxy at proxyURL, or the proxy
+// set in the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables if
+// proxyURL is nil. Credentials in the proxy URL are sent as Proxy-Authorization.
+func WithProxy(proxyURL *url.URL) HTTPClientOption {
+	return func(transport *http.Transport) {
+		if proxyURL == nil {
+			transport.Proxy = http.ProxyFromEnvironment
+			return
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+}
+
+// WithHTTP2 attempts HTTP/2 for TLS connections, which the transport otherwise
+// only does without a custom TLS configuration
+func WithHTTP2() HTTPClientOption {
+	return func(transport *http.Transport) {
+		transport.ForceAttemptHTTP2 = true
+	}
+}
+
+// WithIdleConnTimeout closes the connections that stay idle longer than idleConnTimeout
+func WithIdleConnTimeout(idleConnTimeout time.Duration) HTTPClientOption {
+	return func(transport *http.Transport) {
+		transport.IdleConnTimeout = idleConnTimeout
+	}
+}
+
+// WithKeepAlive sends TCP keep-alive probes on the connections every keepAlive,
+// so that idle connections to gateways are not dropped silently
+func WithKeepAlive(keepAlive time.Duration) HTTPClientOption {
+	return func(transport *http.Transport) {
+		transport.DialContext = (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: keepAlive,
+		}).DialContext
+	}
+}
+
 // CreateHTTPClient returns a new HTTP client with the timeout set to
 // timeoutMS milliseconds, or 300 milliseconds if timeoutMS <= 0.
 // unsafeSsl parameter allows to avoid tls cert validation if it's required
-func CreateHTTPClient(timeout time.Duration, unsafeSsl bool) *http.Client {
+func CreateHTTPClient(timeout time.Duration, unsafeSsl bool, options ...HTTPClientOption) *http.Client {
+	return CreateHTTPClientWithTLS(timeout, &tls.Config{InsecureSkipVerify: unsafeSsl}, options...)
+}
+
+// CreateHTTPClientWithTLS returns a new HTTP client like CreateHTTPClient,
+// using tlsConfig for the TLS connections, e.g. to present client certificates
+// or trust a custom CA. A nil tlsConfig uses the default TLS configuration.
+func CreateHTTPClientWithTLS(timeout time.Duration, tlsConfig *tls.Config, options ...HTTPClientOption) *http.Client {
 	// default the timeout to 300ms
 	if timeout <= 0 {
 		timeout = 300 * time.Millisecond
 	}
+	transport := &http.Transport{
+		TLSClientConfig: tlsConfig,
+	}
+	for _, option := range options {
+		option(transport)
+	}
 	httpClient := &http.Client{
-		Timeout: timeout,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: unsafeSsl},
-		},
+		Timeout:   timeout,
+		Transport: transport,
 	}
 
 	return httpClient
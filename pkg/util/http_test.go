@@ -0,0 +1,165 @@
+/*
+Copyright 2021 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestCreateHTTPClient(t *testing.T) {
+	client := CreateHTTPClient(0, true)
+	if client.Timeout != 300*time.Millisecond {
+		t.Errorf("Wrong default timeout: %s", client.Timeout)
+	}
+	transport := client.Transport.(*http.Transport)
+	if transport.TLSClientConfig == nil || !transport.TLSClientConfig.InsecureSkipVerify {
+		t.Error("Expected TLS verification to be skipped")
+	}
+}
+
+func TestCreateHTTPClientWithTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(server.Certificate())
+
+	testCases := []struct {
+		name      string
+		tlsConfig *tls.Config
+		isError   bool
+	}{
+		{"default config does not trust the server", nil, true},
+		{"server trusted by the custom CA", &tls.Config{RootCAs: rootCAs}, false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			client := CreateHTTPClientWithTLS(time.Second, testCase.tlsConfig)
+			if client.Timeout != time.Second {
+				t.Errorf("Wrong timeout: %s", client.Timeout)
+			}
+			if client.Transport.(*http.Transport).TLSClientConfig != testCase.tlsConfig {
+				t.Error("Expected the TLS config to be used by the transport")
+			}
+
+			resp, err := client.Get(server.URL)
+			if err == nil {
+				resp.Body.Close()
+			}
+			if testCase.isError && err == nil {
+				t.Error("Expected error but got success")
+			}
+			if !testCase.isError && err != nil {
+				t.Error("Expected success but got error", err)
+			}
+		})
+	}
+}
+
+func TestCreateHTTPClientWithProxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.URL.String())
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer proxy.Close()
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := CreateHTTPClient(time.Second, false, WithProxy(proxyURL))
+	resp, err := client.Get("http://target.example/path")
+	if err != nil {
+		t.Fatal("Expected success but got error", err)
+	}
+	resp.Body.Close()
+	if len(proxied) != 1 || proxied[0] != "http://target.example/path" {
+		t.Errorf("Expected the request to be routed through the proxy, got %v", proxied)
+	}
+
+	// without a proxy URL the proxy is read from the environment
+	client = CreateHTTPClient(time.Second, false, WithProxy(nil))
+	if client.Transport.(*http.Transport).Proxy == nil {
+		t.Error("Expected the proxy to be read from the environment")
+	}
+
+	// by default no proxy is used
+	client = CreateHTTPClient(time.Second, false)
+	if client.Transport.(*http.Transport).Proxy != nil {
+		t.Error("Expected no proxy by default")
+	}
+}
+
+func TestCreateHTTPClientWithHTTP2(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(server.Certificate())
+
+	testCases := []struct {
+		name          string
+		options       []HTTPClientOption
+		expectedProto int
+	}{
+		{"custom TLS config falls back to HTTP/1.1", nil, 1},
+		{"HTTP/2 forced", []HTTPClientOption{WithHTTP2()}, 2},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			client := CreateHTTPClientWithTLS(time.Second, &tls.Config{RootCAs: rootCAs}, testCase.options...)
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Fatal("Expected success but got error", err)
+			}
+			resp.Body.Close()
+			if resp.ProtoMajor != testCase.expectedProto {
+				t.Errorf("Wrong protocol %s, expected HTTP/%d", resp.Proto, testCase.expectedProto)
+			}
+		})
+	}
+}
+
+func TestCreateHTTPClientWithKeepAlive(t *testing.T) {
+	client := CreateHTTPClient(time.Second, false, WithIdleConnTimeout(90*time.Second), WithKeepAlive(45*time.Second))
+	transport := client.Transport.(*http.Transport)
+	if transport.IdleConnTimeout != 90*time.Second {
+		t.Errorf("Wrong idle connection timeout: %s", transport.IdleConnTimeout)
+	}
+	if transport.DialContext == nil {
+		t.Error("Expected a dialer with the keep-alive interval")
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal("Expected success but got error", err)
+	}
+	resp.Body.Close()
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2021 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy configures the retries of Retry and DoWithRetry
+type RetryPolicy struct {
+	// Count is the number of retries after the first attempt
+	Count int
+	// Interval is the backoff before the first retry, it doubles with every further retry
+	Interval time.Duration
+	// RetryableStatuses are the response status codes worth retrying, e.g. 502, 503 and 504
+	RetryableStatuses []int
+}
+
+// Backoff returns the backoff before the retry following the attempt, counted from 0. It is picked at
+// random between half and all of the exponential backoff so that clients failing together don't retry together.
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	backoff := p.Interval
+	if backoff <= 0 {
+		return 0
+	}
+	for i := 0; i < attempt && backoff <= math.MaxInt64/2; i++ {
+		backoff *= 2
+	}
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(backoff-half)+1))
+}
+
+// Wait waits for the backoff before the retry following the attempt, or returns the error of the context if it ends first
+func (p RetryPolicy) Wait(ctx context.Context, attempt int) error {
+	timer := time.NewTimer(p.Backoff(attempt))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (p RetryPolicy) isRetryableStatus(statusCode int) bool {
+	for _, status := range p.RetryableStatuses {
+		if status == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// Retry runs the operation until it succeeds, fails with an error it doesn't report as retryable or the retries
+// are exhausted, waiting for the backoff between attempts. Retries stop as soon as the context ends. The retryable
+// flag and error of the last attempt are returned, or the error of the context if it ended during a backoff.
+func (p RetryPolicy) Retry(ctx context.Context, operation func(attempt int) (bool, error)) (bool, error) {
+	for attempt := 0; ; attempt++ {
+		retryable, err := operation(attempt)
+		if err == nil || !retryable || attempt >= p.Count || ctx.Err() != nil {
+			return retryable, err
+		}
+		if err := p.Wait(ctx, attempt); err != nil {
+			return false, err
+		}
+	}
+}
+
+// errRetryableStatus fails the attempts of DoWithRetry answered with a retryable status
+var errRetryableStatus = errors.New("retryable response status")
+
+// DoWithRetry sends the request, retrying network errors and responses with a retryable status as configured
+// by the policy. The last response is returned as is, even with a retryable status. Retries stop as soon as the
+// context of the request ends. Requests with a body are only retried if it can be read again through GetBody,
+// which http.NewRequest sets for the usual body types.
+func DoWithRetry(client HTTPDoer, req *http.Request, policy RetryPolicy) (*http.Response, error) {
+	hasBody := req.Body != nil && req.Body != http.NoBody
+	rewindable := !hasBody || req.GetBody != nil
+
+	var resp *http.Response
+	_, err := policy.Retry(req.Context(), func(attempt int) (bool, error) {
+		if resp != nil {
+			// drain the body so that the connection can be reused by the retry
+			_, _ = io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+			resp = nil
+		}
+		if attempt > 0 && hasBody {
+			body, err := req.GetBody()
+			if err != nil {
+				return false, err
+			}
+			req.Body = body
+		}
+
+		var err error
+		resp, err = client.Do(req)
+		if err != nil {
+			return rewindable, err
+		}
+		if policy.isRetryableStatus(resp.StatusCode) {
+			return rewindable, errRetryableStatus
+		}
+		return false, nil
+	})
+	switch {
+	case errors.Is(err, errRetryableStatus):
+		return resp, nil
+	case err != nil && resp != nil:
+		// the context ended while waiting to retry the response
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp, err
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2021 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{Interval: 100 * time.Millisecond}
+	for attempt, max := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond} {
+		for i := 0; i < 20; i++ {
+			if backoff := policy.Backoff(attempt); backoff < max/2 || backoff > max {
+				t.Errorf("Backoff of attempt %d out of range: %s, expected between %s and %s", attempt, backoff, max/2, max)
+			}
+		}
+	}
+	if backoff := policy.Backoff(100); backoff <= 0 {
+		t.Errorf("Expected a positive backoff for a large attempt but got %s", backoff)
+	}
+	if backoff := (RetryPolicy{}).Backoff(3); backoff != 0 {
+		t.Errorf("Expected no backoff without an interval but got %s", backoff)
+	}
+}
+
+func TestRetryPolicyRetry(t *testing.T) {
+	errFailed := errors.New("failed")
+	testCases := []struct {
+		name      string
+		failures  int
+		retryable bool
+		attempts  int
+		isError   bool
+	}{
+		{"success", 0, true, 1, false},
+		{"success after retries", 2, true, 3, false},
+		{"retries exhausted", 5, true, 3, true},
+		{"not retryable", 5, false, 1, true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var attempts int
+			policy := RetryPolicy{Count: 2, Interval: time.Millisecond}
+			_, err := policy.Retry(context.Background(), func(attempt int) (bool, error) {
+				if attempt != attempts {
+					t.Errorf("Wrong attempt %d, expected %d", attempt, attempts)
+				}
+				attempts++
+				if attempts <= testCase.failures {
+					return testCase.retryable, errFailed
+				}
+				return false, nil
+			})
+			if testCase.isError != (err != nil) {
+				t.Errorf("Expected error %t but got %v", testCase.isError, err)
+			}
+			if attempts != testCase.attempts {
+				t.Errorf("Expected %d attempts but got %d", testCase.attempts, attempts)
+			}
+		})
+	}
+
+	// the context ending stops the retries
+	ctx, cancel := context.WithCancel(context.Background())
+	var attempts int
+	_, err := RetryPolicy{Count: 5, Interval: time.Hour}.Retry(ctx, func(int) (bool, error) {
+		attempts++
+		cancel()
+		return true, errFailed
+	})
+	if !errors.Is(err, errFailed) || attempts != 1 {
+		t.Errorf("Expected a single attempt failing with %q but got %d attempts and %v", errFailed, attempts, err)
+	}
+}
+
+func TestDoWithRetry(t *testing.T) {
+	testCases := []struct {
+		name     string
+		statuses []int
+		count    int
+		status   int
+		requests int
+	}{
+		{"success", []int{http.StatusOK}, 3, http.StatusOK, 1},
+		{"retryable status then success", []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK}, 3, http.StatusOK, 3},
+		{"retries exhausted", []int{http.StatusServiceUnavailable}, 2, http.StatusServiceUnavailable, 3},
+		{"status not retryable", []int{http.StatusNotFound, http.StatusOK}, 3, http.StatusNotFound, 1},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var requests int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if body, _ := ioutil.ReadAll(r.Body); string(body) != "payload" {
+					t.Errorf("Expected the body to be sent with every attempt but got %q", body)
+				}
+				status := testCase.statuses[len(testCase.statuses)-1]
+				if requests < len(testCase.statuses) {
+					status = testCase.statuses[requests]
+				}
+				requests++
+				w.WriteHeader(status)
+			}))
+			defer server.Close()
+
+			req, err := http.NewRequest("POST", server.URL, strings.NewReader("payload"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			policy := RetryPolicy{Count: testCase.count, Interval: time.Millisecond, RetryableStatuses: []int{http.StatusBadGateway, http.StatusServiceUnavailable}}
+			resp, err := DoWithRetry(http.DefaultClient, req, policy)
+			if err != nil {
+				t.Fatal("Expected success but got error", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != testCase.status {
+				t.Errorf("Wrong status: %d, expected: %d", resp.StatusCode, testCase.status)
+			}
+			if requests != testCase.requests {
+				t.Errorf("Wrong number of requests: %d, expected: %d", requests, testCase.requests)
+			}
+		})
+	}
+}
+
+func TestDoWithRetryNetworkErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	var attempts int
+	client := doerFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		return http.DefaultClient.Do(req)
+	})
+	req, err := http.NewRequest("GET", server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DoWithRetry(client, req, RetryPolicy{Count: 2, Interval: time.Millisecond}); err == nil {
+		t.Error("Expected error but got success")
+	}
+	if attempts != 3 {
+		t.Errorf("Wrong number of attempts: %d, expected: 3", attempts)
+	}
+}
+
+func TestDoWithRetryStopsOnContextCancellation(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	policy := RetryPolicy{Count: 10, Interval: time.Hour, RetryableStatuses: []int{http.StatusServiceUnavailable}}
+	_, err = DoWithRetry(http.DefaultClient, req, policy)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the context error but got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the backoff to stop with the context but it took %s", elapsed)
+	}
+	if requests != 1 {
+		t.Errorf("Wrong number of requests: %d, expected: 1", requests)
+	}
+}
+
+type doerFunc func(*http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}